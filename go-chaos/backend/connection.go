@@ -17,6 +17,8 @@ package backend
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/camunda/zeebe-chaos/go-chaos/internal"
 	"github.com/camunda/zeebe/clients/go/v8/pkg/zbc"
@@ -45,12 +47,7 @@ func ConnectBrokers(kubeConfigPath string, namespace string) error {
 	}
 
 	for _, pod := range podNames {
-		err = internal.MakeIpReachableForPod(k8Client, pod)
-		if err != nil {
-			internal.LogVerbose("Error on connection Broker: %s. Error: %s", pod, err.Error())
-		} else {
-			internal.LogInfo("Connected %s again, removed unreachable routes.", pod)
-		}
+		removeNetworkFaults(k8Client, pod, nil)
 	}
 	return nil
 }
@@ -80,15 +77,106 @@ func ConnectGateway(kubeConfigPath string, namespace string) error {
 		return err
 	}
 
+	var brokerIps []string
 	for _, brokerPod := range brokerPods.Items {
-		err = internal.MakeIpReachable(k8Client, gatewayPod.Name, brokerPod.Status.PodIP)
+		brokerIps = append(brokerIps, brokerPod.Status.PodIP)
+	}
+	removeNetworkFaults(k8Client, gatewayPod.Name, brokerIps)
+	return nil
+}
+
+// Removes all network faults from the given pod, only the unreachable routes towards the given ips are removed if
+// ips are given. The faults are removed with one debug container, to not install the tools several times.
+func removeNetworkFaults(k8Client internal.K8Client, podName string, unreachableIps []string) {
+	err := internal.RemoveNetworkFaultsForPod(k8Client, podName, unreachableIps)
+	if err != nil {
+		internal.LogVerbose("Error on connecting %s. Error: %s", podName, err.Error())
+	} else {
+		internal.LogInfo("Connected %s again, removed unreachable routes, traffic control and iptables rules, and stopped dnsmasq (of nxdomain dns faults).", podName)
+	}
+}
+
+// NetworkFault describes a network fault which is currently installed on a pod
+type NetworkFault struct {
+	// the pod on which the fault is installed, e.g. 'zeebe-1 (node 1)'
+	Pod string
+	// the kind of the fault, e.g. 'unreachable', 'blocked ports 26501' or 'netem limit 1000 delay 100ms'
+	Fault string
+	// the pod (or ip, if it doesn't belong to a Zeebe pod) towards which the traffic is affected, the hostname for dns faults
+	Target string
+}
+
+// Lists the network faults which are currently installed on the brokers and gateways.
+func GetNetworkFaults(kubeConfigPath string, namespace string) ([]NetworkFault, error) {
+	k8Client, err := internal.CreateK8Client(kubeConfigPath, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	brokerPods, err := k8Client.GetBrokerPods()
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayPods, err := k8Client.GetGatewayPods()
+	if err != nil {
+		return nil, err
+	}
+
+	podDescriptions := map[string]string{}
+	ipToPod := map[string]string{internal.AllTrafficIp: "all"}
+	for _, pod := range brokerPods.Items {
+		podDescriptions[pod.Name] = pod.Name
+		if nodeId, err := internal.GetBrokerNodeIdOfPod(pod.Name); err == nil {
+			podDescriptions[pod.Name] = fmt.Sprintf("%s (node %d)", pod.Name, nodeId)
+		}
+		ipToPod[pod.Status.PodIP] = podDescriptions[pod.Name]
+	}
+	for _, pod := range gatewayPods.Items {
+		podDescriptions[pod.Name] = pod.Name
+		ipToPod[pod.Status.PodIP] = pod.Name
+	}
+
+	resolveTarget := func(ip string) string {
+		if pod, ok := ipToPod[ip]; ok {
+			return pod
+		}
+		return ip
+	}
+
+	var networkFaults []NetworkFault
+	for _, pod := range append(brokerPods.Items, gatewayPods.Items...) {
+		faults, err := internal.GetNetworkFaultsForPod(k8Client, pod.Name)
 		if err != nil {
-			internal.LogVerbose("Error on connection gateway: %s. Error: %s", gatewayPod.Name, err.Error())
-		} else {
-			internal.LogInfo("Connected %s again with %s, removed unreachable routes.", gatewayPod.Name, brokerPod.Name)
+			return nil, err
+		}
+		internal.LogVerbose("Found network faults %+v on %s", faults, pod.Name)
+
+		podDescription := podDescriptions[pod.Name]
+		for _, ip := range faults.UnreachableIps {
+			networkFaults = append(networkFaults, NetworkFault{Pod: podDescription, Fault: "unreachable", Target: resolveTarget(ip)})
+		}
+		for _, trafficControl := range faults.TrafficControl {
+			for _, ip := range trafficControl.Ips {
+				networkFaults = append(networkFaults, NetworkFault{Pod: podDescription, Fault: trafficControl.Qdisc, Target: resolveTarget(ip)})
+			}
+		}
+		blockedIps := make([]string, 0, len(faults.BlockedPorts))
+		for ip := range faults.BlockedPorts {
+			blockedIps = append(blockedIps, ip)
+		}
+		sort.Strings(blockedIps)
+		for _, ip := range blockedIps {
+			networkFaults = append(networkFaults, NetworkFault{Pod: podDescription, Fault: "blocked ports " + faults.BlockedPorts[ip], Target: resolveTarget(ip)})
+		}
+		for _, dnsFault := range faults.DnsFaults {
+			networkFaults = append(networkFaults, NetworkFault{Pod: podDescription, Fault: "dns " + dnsFault.Mode, Target: dnsFault.Hostname})
+		}
+		for _, port := range faults.DnsRedirectPorts {
+			networkFaults = append(networkFaults, NetworkFault{Pod: podDescription, Fault: "dns nxdomain (dnsmasq on port " + port + ")", Target: "all"})
 		}
 	}
-	return nil
+	return networkFaults, nil
 }
 
 type Broker struct {
@@ -101,6 +189,10 @@ type DisconnectBrokerCfg struct {
 	Broker1Cfg   Broker
 	Broker2Cfg   Broker
 	OneDirection bool
+	// if set, only the traffic on the given ports is dropped, otherwise the brokers are completely disconnected
+	Ports []int
+	// if set, the fault is healed automatically after the given duration
+	Duration time.Duration
 }
 
 func DisconnectBroker(kubeConfigPath string, namespace string, disconnectBrokerCfg DisconnectBrokerCfg, credentials *internal.ClientCredentials) error {
@@ -129,13 +221,17 @@ func DisconnectBroker(kubeConfigPath string, namespace string, disconnectBrokerC
 		return nil
 	}
 
-	return disconnectPods(k8Client, broker1Pod, broker2Pod, disconnectBrokerCfg.OneDirection)
+	return disconnectPods(k8Client, broker1Pod, broker2Pod, disconnectBrokerCfg.OneDirection, disconnectBrokerCfg.Ports, disconnectBrokerCfg.Duration)
 }
 
 type DisconnectGatewayCfg struct {
 	DisconnectToAll bool
 	OneDirection    bool
 	BrokerCfg       Broker
+	// if set, only the traffic on the given ports is dropped, otherwise the gateway is completely disconnected
+	Ports []int
+	// if set, the fault is healed automatically after the given duration
+	Duration time.Duration
 }
 
 func DisconnectGateway(kubeConfigPath string, namespace string, disconnectGatewayCfg DisconnectGatewayCfg, credentials *internal.ClientCredentials) error {
@@ -154,7 +250,7 @@ func DisconnectGateway(kubeConfigPath string, namespace string, disconnectGatewa
 		}
 
 		for _, brokerPod := range pods.Items {
-			err := disconnectPods(k8Client, gatewayPod, &brokerPod, disconnectGatewayCfg.OneDirection)
+			err := disconnectPods(k8Client, gatewayPod, &brokerPod, disconnectGatewayCfg.OneDirection, disconnectGatewayCfg.Ports, disconnectGatewayCfg.Duration)
 			if err != nil {
 				return err
 			}
@@ -165,7 +261,7 @@ func DisconnectGateway(kubeConfigPath string, namespace string, disconnectGatewa
 		if err != nil {
 			return err
 		}
-		err = disconnectPods(k8Client, gatewayPod, broker2Pod, disconnectGatewayCfg.OneDirection)
+		err = disconnectPods(k8Client, gatewayPod, broker2Pod, disconnectGatewayCfg.OneDirection, disconnectGatewayCfg.Ports, disconnectGatewayCfg.Duration)
 		if err != nil {
 			return err
 		}
@@ -173,6 +269,280 @@ func DisconnectGateway(kubeConfigPath string, namespace string, disconnectGatewa
 	return nil
 }
 
+type DisconnectGroupsCfg struct {
+	// the node ids of each group, every pair of brokers from different groups is disconnected
+	Groups [][]int
+	// alternatively to the groups, the brokers can be split into regions; as for the cluster failover, the region of
+	// a broker is its node id modulo the number of regions. The region with the given id is disconnected from all others.
+	Regions      int32
+	RegionId     int32
+	OneDirection bool
+	// if set, only the traffic on the given ports is dropped, otherwise the groups are completely disconnected
+	Ports []int
+	// if set, the fault is healed automatically after the given duration
+	Duration time.Duration
+}
+
+// Disconnects groups of brokers from each other, which allows to simulate a network partition (e.g. a region split).
+func DisconnectGroups(kubeConfigPath string, namespace string, disconnectGroupsCfg DisconnectGroupsCfg) error {
+	k8Client, err := prepareBrokerDisconnect(kubeConfigPath, namespace)
+	if err != nil {
+		return err
+	}
+
+	pods, err := k8Client.GetBrokerPods()
+	if err != nil {
+		return err
+	}
+
+	if len(pods.Items) <= 0 {
+		errorMsg := fmt.Sprintf("Expected to find brokers in current namespace %s, but found nothing", k8Client.GetCurrentNamespace())
+		return errors.New(errorMsg)
+	}
+
+	groups := disconnectGroupsCfg.Groups
+	if disconnectGroupsCfg.Regions > 0 {
+		groups = splitIntoRegions(len(pods.Items), disconnectGroupsCfg.Regions, disconnectGroupsCfg.RegionId)
+	}
+
+	pairs, err := crossGroupPairs(groups, len(pods.Items))
+	if err != nil {
+		return err
+	}
+
+	internal.LogInfo("Disconnect broker groups %v from each other", groups)
+	for _, pair := range pairs {
+		pod1, err := internal.GetBrokerPodForNodeId(k8Client, int32(pair[0]))
+		if err != nil {
+			return err
+		}
+		pod2, err := internal.GetBrokerPodForNodeId(k8Client, int32(pair[1]))
+		if err != nil {
+			return err
+		}
+
+		err = disconnectPods(k8Client, pod1, pod2, disconnectGroupsCfg.OneDirection, disconnectGroupsCfg.Ports, disconnectGroupsCfg.Duration)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Splits the node ids [0, brokerCount) into two groups: the brokers of the region with the given id and all others.
+func splitIntoRegions(brokerCount int, regions int32, regionId int32) [][]int {
+	region := []int{}
+	otherRegions := []int{}
+	for nodeId := 0; nodeId < brokerCount; nodeId++ {
+		if int32(nodeId)%regions == regionId {
+			region = append(region, nodeId)
+		} else {
+			otherRegions = append(otherRegions, nodeId)
+		}
+	}
+	return [][]int{region, otherRegions}
+}
+
+// Returns all pairs of node ids, which are part of different groups. Each pair is only returned once.
+func crossGroupPairs(groups [][]int, brokerCount int) ([][2]int, error) {
+	nonEmptyGroups := 0
+	seenNodeIds := map[int]bool{}
+	for _, group := range groups {
+		if len(group) > 0 {
+			nonEmptyGroups++
+		}
+		for _, nodeId := range group {
+			if nodeId < 0 || nodeId >= brokerCount {
+				return nil, fmt.Errorf("expected node ids to be between 0 and %d, but got %d", brokerCount-1, nodeId)
+			}
+			if seenNodeIds[nodeId] {
+				return nil, fmt.Errorf("expected each node id to be part of only one group, but %d is part of multiple groups", nodeId)
+			}
+			seenNodeIds[nodeId] = true
+		}
+	}
+
+	if nonEmptyGroups < 2 {
+		return nil, fmt.Errorf("expected at least two non-empty groups to disconnect, but got %v", groups)
+	}
+
+	var pairs [][2]int
+	for i, group := range groups {
+		for _, otherGroup := range groups[i+1:] {
+			for _, nodeId := range group {
+				for _, otherNodeId := range otherGroup {
+					pairs = append(pairs, [2]int{nodeId, otherNodeId})
+				}
+			}
+		}
+	}
+	return pairs, nil
+}
+
+type NetemBrokerCfg struct {
+	Broker1Cfg   Broker
+	Broker2Cfg   Broker
+	OneDirection bool
+	Netem        internal.NetemCfg
+	// if set, the fault is healed automatically after the given duration
+	Duration time.Duration
+}
+
+// Applies the given netem configuration (e.g. delay, packet loss) on the traffic between two brokers.
+func NetemBroker(kubeConfigPath string, namespace string, netemBrokerCfg NetemBrokerCfg, credentials *internal.ClientCredentials) error {
+	k8Client, err := prepareBrokerDisconnect(kubeConfigPath, namespace)
+	if err != nil {
+		return err
+	}
+
+	zbClient, closeFn, err := ConnectToZeebeCluster(k8Client, credentials)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	broker1 := netemBrokerCfg.Broker1Cfg
+	broker1Pod, err := getBrokerPod(k8Client, zbClient, broker1.NodeId, broker1.PartitionId, broker1.Role)
+	if err != nil {
+		return err
+	}
+
+	broker2 := netemBrokerCfg.Broker2Cfg
+	broker2Pod, err := getBrokerPod(k8Client, zbClient, broker2.NodeId, broker2.PartitionId, broker2.Role)
+	if err != nil {
+		return err
+	}
+
+	if broker1Pod.Name == broker2Pod.Name {
+		internal.LogInfo("Expected to apply '%s' between two DIFFERENT brokers %s and %s, but they are the same. Will do nothing.", netemBrokerCfg.Netem, broker1Pod.Name, broker2Pod.Name)
+		return nil
+	}
+
+	return applyFaultBetweenPods(k8Client, broker1Pod, broker2Pod, netemBrokerCfg.OneDirection, netemFault(netemBrokerCfg.Netem, netemBrokerCfg.Duration), netemLogFormat(netemBrokerCfg.Netem))
+}
+
+type NetemGatewayCfg struct {
+	ToAll        bool
+	OneDirection bool
+	BrokerCfg    Broker
+	Netem        internal.NetemCfg
+	// if set, the fault is healed automatically after the given duration
+	Duration time.Duration
+}
+
+// Applies the given netem configuration (e.g. delay, packet loss) on the traffic between the gateway and the broker(s).
+func NetemGateway(kubeConfigPath string, namespace string, netemGatewayCfg NetemGatewayCfg, credentials *internal.ClientCredentials) error {
+	k8Client, zbClient, closeFn, err := prepareGatewayDisconnect(kubeConfigPath, namespace, credentials)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	gatewayPod, err := getGatewayPod(k8Client)
+	if err != nil {
+		return err
+	}
+
+	fault := netemFault(netemGatewayCfg.Netem, netemGatewayCfg.Duration)
+	logFormat := netemLogFormat(netemGatewayCfg.Netem)
+	if netemGatewayCfg.ToAll {
+		pods, err := k8Client.GetBrokerPods()
+		if err != nil {
+			return err
+		}
+
+		for _, brokerPod := range pods.Items {
+			err := applyFaultBetweenPods(k8Client, gatewayPod, &brokerPod, netemGatewayCfg.OneDirection, fault, logFormat)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	brokerCfg := netemGatewayCfg.BrokerCfg
+	brokerPod, err := getBrokerPod(k8Client, zbClient, brokerCfg.NodeId, brokerCfg.PartitionId, brokerCfg.Role)
+	if err != nil {
+		return err
+	}
+	return applyFaultBetweenPods(k8Client, gatewayPod, brokerPod, netemGatewayCfg.OneDirection, fault, logFormat)
+}
+
+type ThrottleBrokerCfg struct {
+	BrokerCfg Broker
+	// the peers towards which the traffic is throttled, if empty all egress traffic of the broker is throttled
+	Peers []Broker
+	// the bandwidth limit, e.g. 1mbit
+	Rate string
+	// the size of the token bucket, e.g. 32kbit
+	Burst string
+	// if set, the fault is healed automatically after the given duration
+	Duration time.Duration
+}
+
+// Throttles the egress bandwidth of a broker, optionally only towards the given peers.
+func ThrottleBroker(kubeConfigPath string, namespace string, throttleBrokerCfg ThrottleBrokerCfg, credentials *internal.ClientCredentials) error {
+	k8Client, err := prepareBrokerDisconnect(kubeConfigPath, namespace)
+	if err != nil {
+		return err
+	}
+
+	zbClient, closeFn, err := ConnectToZeebeCluster(k8Client, credentials)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	brokerCfg := throttleBrokerCfg.BrokerCfg
+	brokerPod, err := getBrokerPod(k8Client, zbClient, brokerCfg.NodeId, brokerCfg.PartitionId, brokerCfg.Role)
+	if err != nil {
+		return err
+	}
+
+	var peerIps []string
+	var peerNames []string
+	for _, peer := range throttleBrokerCfg.Peers {
+		peerPod, err := getBrokerPod(k8Client, zbClient, peer.NodeId, peer.PartitionId, peer.Role)
+		if err != nil {
+			return err
+		}
+
+		if peerPod.Name == brokerPod.Name {
+			internal.LogInfo("Peer %s is the throttled broker itself, will ignore it.", peerPod.Name)
+			continue
+		}
+		peerIps = append(peerIps, peerPod.Status.PodIP)
+		peerNames = append(peerNames, peerPod.Name)
+	}
+
+	if len(throttleBrokerCfg.Peers) > 0 && len(peerIps) == 0 {
+		internal.LogInfo("Expected to throttle traffic of %s towards DIFFERENT brokers, but found no other peer. Will do nothing.", brokerPod.Name)
+		return nil
+	}
+
+	err = internal.ThrottleBandwidthForPod(k8Client, brokerPod.Name, throttleBrokerCfg.Rate, throttleBrokerCfg.Burst, peerIps, throttleBrokerCfg.Duration)
+	if err != nil {
+		return err
+	}
+
+	if len(peerNames) == 0 {
+		internal.LogInfo("Throttled egress bandwidth of %s to %s", brokerPod.Name, throttleBrokerCfg.Rate)
+	} else {
+		internal.LogInfo("Throttled egress bandwidth of %s towards %v to %s", brokerPod.Name, peerNames, throttleBrokerCfg.Rate)
+	}
+	return nil
+}
+
+func netemFault(netemCfg internal.NetemCfg, duration time.Duration) podFault {
+	return func(k8Client internal.K8Client, targetIp string, podName string) error {
+		return internal.AddNetemForPod(k8Client, targetIp, podName, netemCfg, duration)
+	}
+}
+
+func netemLogFormat(netemCfg internal.NetemCfg) string {
+	return fmt.Sprintf("Applied '%s' on traffic from %%s to %%s", netemCfg)
+}
+
 func prepareGatewayDisconnect(kubeConfigPath string, namespace string, credentials *internal.ClientCredentials) (internal.K8Client, zbc.Client, func(), error) {
 	k8Client, err := prepareBrokerDisconnect(kubeConfigPath, namespace)
 	if err != nil {
@@ -225,7 +595,10 @@ func getGatewayPod(k8Client internal.K8Client) (*v1.Pod, error) {
 
 func getBrokerPod(k8Client internal.K8Client, zbClient zbc.Client, brokerNodeId int, brokerPartitionId int, brokerRole string) (*v1.Pod, error) {
 	var brokerPod *v1.Pod
-	var err error
+	brokerNodeId, brokerPartitionId, brokerRole, err := internal.ResolveRandomTarget(zbClient, brokerNodeId, brokerPartitionId, brokerRole)
+	if err != nil {
+		return nil, err
+	}
 	if brokerNodeId >= 0 {
 		brokerPod, err = internal.GetBrokerPodForNodeId(k8Client, int32(brokerNodeId))
 		internal.LogVerbose("Found Broker %s with node id %d.", brokerPod.Name, brokerNodeId)
@@ -237,20 +610,41 @@ func getBrokerPod(k8Client internal.K8Client, zbClient zbc.Client, brokerNodeId
 	return brokerPod, err
 }
 
-func disconnectPods(k8Client internal.K8Client, firstPod *v1.Pod, secondPod *v1.Pod, oneDirection bool) error {
-	err := internal.MakeIpUnreachableForPod(k8Client, secondPod.Status.PodIP, firstPod.Name)
+// podFault is applied on the pod with the given name and affects the traffic towards the given ip
+type podFault func(k8Client internal.K8Client, targetIp string, podName string) error
+
+// Disconnects the given pods, if ports are given only the traffic on these ports is dropped.
+// If a duration is given, the pods are connected again afterwards.
+func disconnectPods(k8Client internal.K8Client, firstPod *v1.Pod, secondPod *v1.Pod, oneDirection bool, ports []int, duration time.Duration) error {
+	if len(ports) > 0 {
+		blockPorts := func(k8Client internal.K8Client, targetIp string, podName string) error {
+			return internal.BlockPortsForPod(k8Client, targetIp, podName, ports, duration)
+		}
+		return applyFaultBetweenPods(k8Client, firstPod, secondPod, oneDirection, blockPorts, fmt.Sprintf("Blocked ports %v from %%s to %%s", ports))
+	}
+	makeIpUnreachable := func(k8Client internal.K8Client, targetIp string, podName string) error {
+		return internal.MakeIpUnreachableForPod(k8Client, targetIp, podName, duration)
+	}
+	return applyFaultBetweenPods(k8Client, firstPod, secondPod, oneDirection, makeIpUnreachable, "Disconnect %s from %s")
+}
+
+// Applies the given fault on the first pod, for the traffic towards the second pod.
+// If oneDirection is false, the fault is applied the other way around as well.
+// The logFormat is used to log each applied fault, with the affected pod and the target pod as arguments.
+func applyFaultBetweenPods(k8Client internal.K8Client, firstPod *v1.Pod, secondPod *v1.Pod, oneDirection bool, fault podFault, logFormat string) error {
+	err := fault(k8Client, secondPod.Status.PodIP, firstPod.Name)
 	if err != nil {
 		return err
 	}
 
-	internal.LogInfo("Disconnect %s from %s", firstPod.Name, secondPod.Name)
+	internal.LogInfo(logFormat, firstPod.Name, secondPod.Name)
 
 	if !oneDirection {
-		err = internal.MakeIpUnreachableForPod(k8Client, firstPod.Status.PodIP, secondPod.Name)
+		err = fault(k8Client, firstPod.Status.PodIP, secondPod.Name)
 		if err != nil {
 			return err
 		}
-		internal.LogInfo("Disconnect %s from %s", secondPod.Name, firstPod.Name)
+		internal.LogInfo(logFormat, secondPod.Name, firstPod.Name)
 	}
 	return nil
 }
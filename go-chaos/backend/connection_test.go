@@ -0,0 +1,80 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldSplitIntoRegions(t *testing.T) {
+	// given
+	brokerCount := 6
+
+	// when
+	groups := splitIntoRegions(brokerCount, 2, 1)
+
+	// then
+	assert.Equal(t, [][]int{{1, 3, 5}, {0, 2, 4}}, groups)
+}
+
+func Test_ShouldReturnCrossGroupPairs(t *testing.T) {
+	// given
+	groups := [][]int{{0, 1}, {2}, {3}}
+
+	// when
+	pairs, err := crossGroupPairs(groups, 4)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, [][2]int{{0, 2}, {1, 2}, {0, 3}, {1, 3}, {2, 3}}, pairs)
+}
+
+func Test_ShouldRejectNodeInMultipleGroups(t *testing.T) {
+	// given
+	groups := [][]int{{0, 1}, {1, 2}}
+
+	// when
+	_, err := crossGroupPairs(groups, 3)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "part of multiple groups")
+}
+
+func Test_ShouldRejectUnknownNodeId(t *testing.T) {
+	// given
+	groups := [][]int{{0}, {3}}
+
+	// when
+	_, err := crossGroupPairs(groups, 3)
+
+	// then
+	require.Error(t, err)
+}
+
+func Test_ShouldRejectSingleGroup(t *testing.T) {
+	// given
+	groups := [][]int{{0, 1, 2}, {}}
+
+	// when
+	_, err := crossGroupPairs(groups, 3)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at least two non-empty groups")
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"time"
+
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+)
+
+type DnsFaultCfg struct {
+	// the hostnames for which the dns lookups should fail
+	Hostnames []string
+	// how the lookups should fail, one of drop, reject or nxdomain
+	Mode string
+	// if set, the fault is healed automatically after the given duration
+	Duration time.Duration
+}
+
+// Makes the dns lookups of the given hostnames fail on the broker.
+func InjectDnsFaultIntoBroker(kubeConfigPath string, namespace string, brokerCfg Broker, dnsFaultCfg DnsFaultCfg, credentials *internal.ClientCredentials) error {
+	k8Client, err := prepareBrokerDisconnect(kubeConfigPath, namespace)
+	if err != nil {
+		return err
+	}
+
+	zbClient, closeFn, err := ConnectToZeebeCluster(k8Client, credentials)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	brokerPod, err := getBrokerPod(k8Client, zbClient, brokerCfg.NodeId, brokerCfg.PartitionId, brokerCfg.Role)
+	if err != nil {
+		return err
+	}
+
+	return injectDnsFault(k8Client, brokerPod.Name, dnsFaultCfg)
+}
+
+// Makes the dns lookups of the given hostnames fail on the gateway.
+func InjectDnsFaultIntoGateway(kubeConfigPath string, namespace string, dnsFaultCfg DnsFaultCfg) error {
+	k8Client, err := prepareBrokerDisconnect(kubeConfigPath, namespace)
+	if err != nil {
+		return err
+	}
+
+	gatewayPod, err := getGatewayPod(k8Client)
+	if err != nil {
+		return err
+	}
+
+	return injectDnsFault(k8Client, gatewayPod.Name, dnsFaultCfg)
+}
+
+func injectDnsFault(k8Client internal.K8Client, podName string, dnsFaultCfg DnsFaultCfg) error {
+	err := internal.InjectDnsFaultForPod(k8Client, podName, dnsFaultCfg.Hostnames, dnsFaultCfg.Mode, dnsFaultCfg.Duration)
+	if err != nil {
+		return err
+	}
+
+	internal.LogInfo("Injected dns fault (%s) for %v into %s", dnsFaultCfg.Mode, dnsFaultCfg.Hostnames, podName)
+	return nil
+}
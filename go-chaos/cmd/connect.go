@@ -15,7 +15,13 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
 	"github.com/camunda/zeebe-chaos/go-chaos/backend"
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
 	"github.com/spf13/cobra"
 )
 
@@ -46,7 +52,37 @@ func AddConnectCmd(rootCmd *cobra.Command, flags *Flags) {
 		},
 	}
 
+	var connectStatus = &cobra.Command{
+		Use:   "status",
+		Short: "Show active network faults",
+		Long: `Show the network faults (unreachable routes, traffic control and blocked ports) which are currently installed
+on the Zeebe Brokers and Gateway, with the pods towards which the traffic is affected.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			networkFaults, err := backend.GetNetworkFaults(flags.kubeConfigPath, flags.namespace)
+			ensureNoError(err)
+
+			if len(networkFaults) == 0 {
+				internal.LogInfo("No active network faults found.")
+				return
+			}
+
+			builder := strings.Builder{}
+			writeNetworkFaultsToOutput(&builder, networkFaults)
+			internal.LogInfo("%s", builder.String())
+		},
+	}
+
 	rootCmd.AddCommand(connect)
 	connect.AddCommand(connectBrokers)
 	connect.AddCommand(connectGateway)
+	connect.AddCommand(connectStatus)
+}
+
+func writeNetworkFaultsToOutput(output io.Writer, networkFaults []backend.NetworkFault) {
+	writer := tabwriter.NewWriter(output, 10, 0, 2, ' ', tabwriter.Debug)
+	addLineToWriter(writer, "Pod\tFault\tTarget")
+	for _, networkFault := range networkFaults {
+		addLineToWriter(writer, fmt.Sprintf("%s\t%s\t%s", networkFault.Pod, networkFault.Fault, networkFault.Target))
+	}
+	writer.Flush()
 }
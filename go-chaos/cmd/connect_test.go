@@ -0,0 +1,42 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/camunda/zeebe-chaos/go-chaos/backend"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteNetworkFaults(t *testing.T) {
+	// given
+	networkFaults := []backend.NetworkFault{
+		{Pod: "zeebe-0 (node 0)", Fault: "unreachable", Target: "zeebe-1 (node 1)"},
+		{Pod: "zeebe-gateway-abc", Fault: "netem limit 1000 delay 100ms", Target: "all"},
+	}
+	var buf bytes.Buffer
+	expectedOutput := `Pod                |Fault                         |Target
+zeebe-0 (node 0)   |unreachable                   |zeebe-1 (node 1)
+zeebe-gateway-abc  |netem limit 1000 delay 100ms  |all
+`
+
+	// when
+	writeNetworkFaultsToOutput(&buf, networkFaults)
+
+	// then
+	assert.Equal(t, expectedOutput, buf.String())
+}
@@ -53,7 +53,8 @@ func AddDatalossSimulationCmd(rootCmd *cobra.Command, flags *Flags) {
 	var datalossDelete = &cobra.Command{
 		Use:   "delete",
 		Short: "Delete data of a broker",
-		Long:  `Delete data of a broker by deleting the pvc and the pod`,
+		Long: `Delete data of a broker by deleting the pvc and the pod.
+If a partition is given, only the data of this partition is deleted and the pod is restarted.`,
 		Run: func(cmd *cobra.Command, args []string) {
 
 			k8Client, err := createK8ClientWithFlags(flags)
@@ -68,6 +69,32 @@ func AddDatalossSimulationCmd(rootCmd *cobra.Command, flags *Flags) {
 				panic(err)
 			}
 
+			// the partitionId flag is shared with other commands, which overwrite its default, so we rely only on whether it is set
+			if cmd.Flags().Changed("partitionId") {
+				err = internal.DeletePartitionDataOfPod(k8Client, pod.Name, flags.partitionId)
+				if err != nil {
+					internal.LogInfo("Failed to delete data of partition %d on pod %s", flags.partitionId, pod.Name)
+					panic(err)
+				}
+
+				// only block the init container after the data is deleted, otherwise a failed deletion would keep the broker blocked
+				err = internal.SetInitContainerBlockFlag(k8Client, flags.nodeId, "true")
+				if err != nil {
+					panic(err)
+				}
+
+				// restart immediately, such that the broker doesn't write the partition data again on shutdown
+				gracePeriodSec := int64(0)
+				err = k8Client.RestartPodWithGracePeriod(pod.Name, &gracePeriodSec)
+				if err != nil {
+					internal.LogInfo("Failed to restart pod %s", pod.Name)
+					panic(err)
+				}
+
+				internal.LogInfo("Deleted data of partition %d on pod %s in namespace %s", flags.partitionId, pod.Name, k8Client.GetCurrentNamespace())
+				return
+			}
+
 			k8Client.DeletePvcOfBroker(pod.Name)
 
 			internal.SetInitContainerBlockFlag(k8Client, flags.nodeId, "true")
@@ -117,12 +144,59 @@ func AddDatalossSimulationCmd(rootCmd *cobra.Command, flags *Flags) {
 		},
 	}
 
+	var datalossCorrupt = &cobra.Command{
+		Use:   "corrupt",
+		Short: "Corrupt data of a partition on a broker",
+		Long: `Corrupt the latest snapshot or the last sealed journal segment of a partition on a broker, and restart the broker.
+The newest journal segment is not corrupted, since it is preallocated and mostly not written yet.
+The corruption either overwrites bytes in the middle of the file with random bytes, or truncates the file to half of its size.`,
+		Run: func(cmd *cobra.Command, args []string) {
+
+			k8Client, err := createK8ClientWithFlags(flags)
+			if err != nil {
+				panic(err)
+			}
+
+			pod, err := internal.GetBrokerPodForNodeId(k8Client, int32(flags.nodeId))
+
+			if err != nil {
+				internal.LogInfo("Failed to get pod with nodeId %d %s", flags.nodeId, err)
+				panic(err)
+			}
+
+			file, err := internal.CorruptPartitionDataOfPod(k8Client, pod.Name, flags.partitionId, flags.corruptionTarget, flags.corruptionMode)
+			if err != nil {
+				internal.LogInfo("Failed to corrupt %s of partition %d on pod %s", flags.corruptionTarget, flags.partitionId, pod.Name)
+				panic(err)
+			}
+			internal.LogInfo("Corrupted (%s) file %s on pod %s", flags.corruptionMode, file, pod.Name)
+
+			// the broker detects the corruption only when reading the file again, which happens on restart
+			gracePeriodSec := int64(0)
+			err = k8Client.RestartPodWithGracePeriod(pod.Name, &gracePeriodSec)
+			if err != nil {
+				internal.LogInfo("Failed to restart pod %s", pod.Name)
+				panic(err)
+			}
+
+			internal.LogInfo("Restarted pod %s in namespace %s", pod.Name, k8Client.GetCurrentNamespace())
+		},
+	}
+
 	rootCmd.AddCommand(datalossCmd)
 	datalossCmd.AddCommand(prepareCmd)
 	datalossCmd.AddCommand(datalossDelete)
 	datalossCmd.AddCommand(datalossRecover)
+	datalossCmd.AddCommand(datalossCorrupt)
 
 	datalossDelete.Flags().IntVar(&flags.nodeId, "nodeId", 1, "Specify the id of the broker")
+	datalossDelete.Flags().IntVar(&flags.partitionId, "partitionId", 0, "Specify the id of the partition whose data should be deleted, per default the whole pvc is deleted")
 	datalossRecover.Flags().IntVar(&flags.nodeId, "nodeId", 1, "Specify the id of the broker")
 	datalossRecover.Flags().BoolVar(&flags.awaitReadiness, "awaitReadiness", true, "If true wait until the recovered pod is ready")
+	datalossCorrupt.Flags().IntVar(&flags.nodeId, "nodeId", 1, "Specify the id of the broker")
+	// the nodeId flag is shared with other commands, which overwrite its default
+	datalossCorrupt.MarkFlagRequired("nodeId")
+	datalossCorrupt.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the id of the partition whose data should be corrupted")
+	datalossCorrupt.Flags().StringVar(&flags.corruptionTarget, "target", internal.CorruptionTargetJournal, "Specify the data which should be corrupted [snapshot, journal]")
+	datalossCorrupt.Flags().StringVar(&flags.corruptionMode, "mode", internal.CorruptionModeFlip, "Specify how the data should be corrupted [flip, truncate]")
 }
@@ -77,13 +77,27 @@ Useful for experimenting with deployment distribution.`,
 		Use:   "worker",
 		Short: "Deploy a worker deployment to the Zeebe cluster",
 		Long: `Deploy a worker deployment to the Zeebe cluster. 
-The workers can be used as part of some chaos experiments to complete process instances etc.`,
+The workers can be used as part of some chaos experiments to complete process instances etc.
+If a failure, BPMN error or timeout percentage is set, the zbchaos benchmark worker (see 'worker benchmark') is deployed instead,
+which fails, rejects or doesn't complete the given percentages of the jobs. It uses the zbchaos image with the given zbchaosImageTag.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			k8Client, err := createK8ClientWithFlags(flags)
 			ensureNoError(err)
 
 			credentials := makeClientCredentials(flags)
-			err = k8Client.CreateWorkerDeployment(DockerImageTag, flags.pollingDelayMs, credentials)
+			options := internal.DefaultWorkerOptions(DockerImageTag, flags.pollingDelayMs)
+			options.Replicas = flags.deployWorkerReplicas
+			options.JobType = flags.workerJobType
+			options.CompletionDelay = flags.completionDelay
+			options.FailurePercentage = flags.failurePercentage
+			options.BpmnErrorPercentage = flags.bpmnErrorPercentage
+			options.TimeoutPercentage = flags.timeoutPercentage
+			options.ChaosImageTag = flags.zbchaosImageTag
+			options.CpuRequest = flags.workerCpuRequest
+			options.MemoryRequest = flags.workerMemoryRequest
+			options.CpuLimit = flags.workerCpuLimit
+			options.MemoryLimit = flags.workerMemoryLimit
+			err = k8Client.CreateWorkerDeploymentWithOptions(options, credentials)
 			ensureNoError(err)
 
 			internal.LogInfo("Worker successfully deployed to the current namespace: %s", k8Client.GetCurrentNamespace())
@@ -123,6 +137,18 @@ The process models allow to execute chaos experiments.`,
 
 	deployCmd.AddCommand(deployWorkerCmd)
 	deployWorkerCmd.Flags().IntVar(&flags.pollingDelayMs, "pollingDelay", 1, "Specifies the worker's polling interval in milliseconds")
+	defaultWorkerOptions := internal.DefaultWorkerOptions(DockerImageTag, 1)
+	deployWorkerCmd.Flags().Int32Var(&flags.deployWorkerReplicas, "replicas", defaultWorkerOptions.Replicas, "Specifies the count of worker replicas")
+	deployWorkerCmd.Flags().StringVar(&flags.workerJobType, "jobType", defaultWorkerOptions.JobType, "Specifies the type of the jobs, which are handled by the workers")
+	deployWorkerCmd.Flags().DurationVar(&flags.completionDelay, "completionDelay", defaultWorkerOptions.CompletionDelay, "Specifies how long the workers take to complete a job")
+	deployWorkerCmd.Flags().Float64Var(&flags.failurePercentage, "failurePercentage", 0, "Specifies the percentage of jobs, which are failed by the workers (with decreased retries)")
+	deployWorkerCmd.Flags().Float64Var(&flags.bpmnErrorPercentage, "bpmnErrorPercentage", 0, "Specifies the percentage of jobs, for which the workers throw a BPMN error")
+	deployWorkerCmd.Flags().Float64Var(&flags.timeoutPercentage, "timeoutPercentage", 0, "Specifies the percentage of jobs, which are never completed by the workers, such that they time out")
+	deployWorkerCmd.Flags().StringVar(&flags.zbchaosImageTag, "zbchaosImageTag", Version, "Specifies the tag of the zbchaos image, which is used for the benchmark worker if any percentage is set")
+	deployWorkerCmd.Flags().StringVar(&flags.workerCpuRequest, "cpuRequest", defaultWorkerOptions.CpuRequest, "Specifies the CPU request of each worker")
+	deployWorkerCmd.Flags().StringVar(&flags.workerMemoryRequest, "memoryRequest", defaultWorkerOptions.MemoryRequest, "Specifies the memory request of each worker")
+	deployWorkerCmd.Flags().StringVar(&flags.workerCpuLimit, "cpuLimit", defaultWorkerOptions.CpuLimit, "Specifies the CPU limit of each worker")
+	deployWorkerCmd.Flags().StringVar(&flags.workerMemoryLimit, "memoryLimit", defaultWorkerOptions.MemoryLimit, "Specifies the memory limit of each worker")
 
 	deployCmd.AddCommand(deployChaosModels)
 }
@@ -15,7 +15,12 @@
 package cmd
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/camunda/zeebe-chaos/go-chaos/backend"
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +30,23 @@ func ensureNoError(err error) {
 	}
 }
 
+// Adds the duration flag to the given fault command, after which the fault is healed automatically.
+func addDurationFlag(cmd *cobra.Command, flags *Flags) {
+	cmd.Flags().DurationVar(&flags.duration, "duration", 0, "Specify after which duration (e.g. 5m) the fault should be healed automatically, if not set the fault stays until it is healed manually")
+	cmd.PostRun = func(cmd *cobra.Command, args []string) {
+		if flags.duration > 0 {
+			internal.LogInfo("The fault will be healed automatically after %s.", flags.duration)
+		}
+	}
+}
+
+// Adds the duration flag like addDurationFlag, but for faults which can't stay until they are healed manually.
+func addRequiredDurationFlag(cmd *cobra.Command, flags *Flags) {
+	addDurationFlag(cmd, flags)
+	cmd.Flags().Lookup("duration").Usage = "Specify after which duration (e.g. 30s) the fault should be healed automatically"
+	cmd.MarkFlagRequired("duration")
+}
+
 func AddDisconnectCommand(rootCmd *cobra.Command, flags *Flags) {
 	disconnect := &cobra.Command{
 		Use:   "disconnect",
@@ -49,6 +71,8 @@ func AddDisconnectCommand(rootCmd *cobra.Command, flags *Flags) {
 					Role:        flags.broker2Role,
 				},
 				OneDirection: flags.oneDirection,
+				Ports:        flags.ports,
+				Duration:     flags.duration,
 			},
 				makeClientCredentials(flags),
 			)
@@ -64,6 +88,8 @@ func AddDisconnectCommand(rootCmd *cobra.Command, flags *Flags) {
 			err := backend.DisconnectGateway(flags.kubeConfigPath, flags.namespace, backend.DisconnectGatewayCfg{
 				OneDirection:    flags.oneDirection,
 				DisconnectToAll: flags.disconnectToAll,
+				Ports:           flags.ports,
+				Duration:        flags.duration,
 				BrokerCfg: backend.Broker{
 					Role:        flags.role,
 					PartitionId: flags.partitionId,
@@ -74,29 +100,87 @@ func AddDisconnectCommand(rootCmd *cobra.Command, flags *Flags) {
 		},
 	}
 
+	disconnectGroups := &cobra.Command{
+		Use:   "groups",
+		Short: "Disconnect groups of Zeebe Brokers",
+		Long: `Disconnect groups of Zeebe Brokers from each other, every broker of a group is disconnected from all brokers of the other groups.
+The groups can be given as comma separated node ids (e.g. '--group 0,1 --group 2,3') or via a region split, where the broker
+with node id N belongs to region N % regions, and the region with the given regionId is disconnected from all other regions.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			groups, err := parseGroups(flags.groups)
+			ensureNoError(err)
+
+			disconnectGroupsCfg := backend.DisconnectGroupsCfg{
+				Groups:       groups,
+				OneDirection: flags.oneDirection,
+				Ports:        flags.ports,
+				Duration:     flags.duration,
+			}
+			if cmd.Flags().Changed("regions") {
+				disconnectGroupsCfg.Regions = flags.regions
+				disconnectGroupsCfg.RegionId = flags.regionId
+			}
+
+			err = backend.DisconnectGroups(flags.kubeConfigPath, flags.namespace, disconnectGroupsCfg)
+			ensureNoError(err)
+		},
+	}
+
 	rootCmd.AddCommand(disconnect)
 
 	// disconnect brokers
 	disconnect.AddCommand(disconnectBrokers)
 	// broker 1
-	disconnectBrokers.Flags().StringVar(&flags.broker1Role, "broker1Role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the first Broker")
-	disconnectBrokers.Flags().IntVar(&flags.broker1PartitionId, "broker1PartitionId", 1, "Specify the partition id of the first Broker")
-	disconnectBrokers.Flags().IntVar(&flags.broker1NodeId, "broker1NodeId", -1, "Specify the nodeId of the first Broker")
+	disconnectBrokers.Flags().StringVar(&flags.broker1Role, "broker1Role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, RANDOM] of the first Broker")
+	targetIdVar(disconnectBrokers.Flags(), &flags.broker1PartitionId, "broker1PartitionId", 1, "Specify the partition id of the first Broker")
+	targetIdVar(disconnectBrokers.Flags(), &flags.broker1NodeId, "broker1NodeId", -1, "Specify the nodeId of the first Broker")
 	disconnectBrokers.MarkFlagsMutuallyExclusive("broker1PartitionId", "broker1NodeId")
 	// broker 2
-	disconnectBrokers.Flags().StringVar(&flags.broker2Role, "broker2Role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the second Broker")
-	disconnectBrokers.Flags().IntVar(&flags.broker2PartitionId, "broker2PartitionId", 2, "Specify the partition id of the second Broker")
-	disconnectBrokers.Flags().IntVar(&flags.broker2NodeId, "broker2NodeId", -1, "Specify the nodeId of the second Broker")
+	disconnectBrokers.Flags().StringVar(&flags.broker2Role, "broker2Role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, RANDOM] of the second Broker")
+	targetIdVar(disconnectBrokers.Flags(), &flags.broker2PartitionId, "broker2PartitionId", 2, "Specify the partition id of the second Broker")
+	targetIdVar(disconnectBrokers.Flags(), &flags.broker2NodeId, "broker2NodeId", -1, "Specify the nodeId of the second Broker")
 	// general
 	disconnectBrokers.Flags().BoolVar(&flags.oneDirection, "one-direction", false, "Specify whether the network partition should be setup only in one direction (asymmetric)")
 	disconnectBrokers.MarkFlagsMutuallyExclusive("broker2PartitionId", "broker2NodeId")
+	addDurationFlag(disconnectBrokers, flags)
+	disconnectBrokers.Flags().IntSliceVar(&flags.ports, "port", nil, "Specify the ports (e.g. 26501 command API, 26502 internal cluster, 9600 management) on which the traffic should be dropped, if not set all traffic is dropped")
 
 	// disconnect gateway
 	disconnect.AddCommand(disconnectGateway)
-	disconnectGateway.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
-	disconnectGateway.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the Broker")
-	disconnectGateway.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the partition id of the Broker")
+	targetIdVar(disconnectGateway.Flags(), &flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	disconnectGateway.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, RANDOM] of the Broker")
+	targetIdVar(disconnectGateway.Flags(), &flags.partitionId, "partitionId", 1, "Specify the partition id of the Broker")
 	disconnectGateway.Flags().BoolVar(&flags.oneDirection, "one-direction", false, "Specify whether the network partition should be setup only in one direction (asymmetric)")
 	disconnectGateway.Flags().BoolVar(&flags.disconnectToAll, "all", false, "Specify whether the gateway should be disconnected to all brokers")
 	disconnectGateway.MarkFlagsMutuallyExclusive("all", "partitionId", "nodeId")
+	addDurationFlag(disconnectGateway, flags)
+	disconnectGateway.Flags().IntSliceVar(&flags.ports, "port", nil, "Specify the ports (e.g. 26501 command API, 26502 internal cluster, 9600 management) on which the traffic should be dropped, if not set all traffic is dropped")
+
+	// disconnect groups
+	disconnect.AddCommand(disconnectGroups)
+	disconnectGroups.Flags().StringArrayVar(&flags.groups, "group", nil, "Specify a group of Brokers as comma separated node ids, can be repeated")
+	disconnectGroups.Flags().Int32Var(&flags.regions, "regions", 1, "The number of regions in the cluster")
+	disconnectGroups.Flags().Int32Var(&flags.regionId, "regionId", 0, "The id of the region to disconnect from the other regions")
+	disconnectGroups.Flags().BoolVar(&flags.oneDirection, "one-direction", false, "Specify whether the network partition should be setup only in one direction (asymmetric)")
+	addDurationFlag(disconnectGroups, flags)
+	disconnectGroups.Flags().IntSliceVar(&flags.ports, "port", nil, "Specify the ports (e.g. 26501 command API, 26502 internal cluster, 9600 management) on which the traffic should be dropped, if not set all traffic is dropped")
+	disconnectGroups.MarkFlagsMutuallyExclusive("group", "regions")
+	disconnectGroups.MarkFlagsMutuallyExclusive("group", "regionId")
+	disconnectGroups.MarkFlagsRequiredTogether("regions", "regionId")
+}
+
+func parseGroups(groups []string) ([][]int, error) {
+	var parsedGroups [][]int
+	for _, group := range groups {
+		var nodeIds []int
+		for _, nodeId := range strings.Split(group, ",") {
+			parsedNodeId, err := strconv.Atoi(strings.TrimSpace(nodeId))
+			if err != nil {
+				return nil, fmt.Errorf("expected group to contain comma separated node ids, but got '%s'", group)
+			}
+			nodeIds = append(nodeIds, parsedNodeId)
+		}
+		parsedGroups = append(parsedGroups, nodeIds)
+	}
+	return parsedGroups, nil
 }
@@ -0,0 +1,159 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func AddDiskCommand(rootCmd *cobra.Command, flags *Flags) {
+	diskCmd := &cobra.Command{
+		Use:   "disk",
+		Short: "Disk faults on a Zeebe broker",
+		Long:  `Disk faults on the data volume of a Zeebe broker, uses sub-commands to fill and release the disk.`,
+	}
+
+	diskFillCmd := &cobra.Command{
+		Use:   "fill",
+		Short: "Fills the data volume of a Zeebe broker",
+		Long: `Fills the data volume of a Zeebe broker with a ballast file, up to the given usage percentage or until only the given free space is left.
+This allows to verify the disk watermarks of the broker. The ballast file can be removed again with the release command.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			diskFillCfg := internal.DiskFillCfg{UsagePercentage: flags.diskUsage}
+			if cmd.Flags().Changed("freeSpace") {
+				diskFillCfg = internal.DiskFillCfg{FreeBytes: parseBytesFlag(flags.diskFreeSpace)}
+			}
+
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			brokerPod := getBrokerPodWithFlags(k8Client, flags)
+			ballastSize, err := internal.FillDiskOfPod(k8Client, brokerPod.Name, diskFillCfg)
+			ensureNoError(err)
+
+			if ballastSize <= 0 {
+				internal.LogInfo("Data volume of %s is already filled up to the threshold, did not add any ballast", brokerPod.Name)
+			} else {
+				internal.LogInfo("Filled data volume of %s with %s ballast", brokerPod.Name, resource.NewQuantity(ballastSize, resource.BinarySI))
+			}
+		},
+	}
+
+	diskReleaseCmd := &cobra.Command{
+		Use:   "release",
+		Short: "Releases the data volume of a Zeebe broker",
+		Long:  `Releases the data volume of a Zeebe broker, by removing the ballast file which has been added by the fill command.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			if flags.all {
+				brokerPodNames, err := k8Client.GetBrokerPodNames()
+				ensureNoError(err)
+
+				if len(brokerPodNames) <= 0 {
+					panic(errors.New(fmt.Sprintf("Expected to find a Zeebe broker in namespace %s, but none found", k8Client.GetCurrentNamespace())))
+				}
+
+				for _, brokerPodName := range brokerPodNames {
+					err = internal.ReleaseDiskOfPod(k8Client, brokerPodName)
+					ensureNoError(err)
+					internal.LogInfo("Released data volume of %s", brokerPodName)
+				}
+				return
+			}
+
+			brokerPod := getBrokerPodWithFlags(k8Client, flags)
+			err = internal.ReleaseDiskOfPod(k8Client, brokerPod.Name)
+			ensureNoError(err)
+			internal.LogInfo("Released data volume of %s", brokerPod.Name)
+		},
+	}
+
+	diskThrottleCmd := &cobra.Command{
+		Use:   "throttle",
+		Short: "Throttles the disk io of a Zeebe broker",
+		Long: `Throttles the read and write bandwidth and operations of the data volume of a Zeebe broker, and resets it after the given duration.
+The limits are applied via the cgroup io.max of the broker container, which requires cgroup v2 on the nodes.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			diskThrottleCfg := internal.DiskThrottleCfg{
+				ReadBps:   parseBytesFlag(flags.diskReadBps),
+				WriteBps:  parseBytesFlag(flags.diskWriteBps),
+				ReadIops:  flags.diskReadIops,
+				WriteIops: flags.diskWriteIops,
+			}
+
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			brokerPod := getBrokerPodWithFlags(k8Client, flags)
+			err = internal.ThrottleDiskOfPod(k8Client, brokerPod.Name, diskThrottleCfg, flags.duration)
+			ensureNoError(err)
+			internal.LogInfo("Throttled disk io of %s to '%s' for %s", brokerPod.Name, diskThrottleCfg, flags.duration)
+		},
+	}
+
+	rootCmd.AddCommand(diskCmd)
+	diskCmd.PersistentFlags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, INACTIVE]")
+	diskCmd.PersistentFlags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the id of the partition")
+	diskCmd.PersistentFlags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+
+	diskCmd.AddCommand(diskFillCmd)
+	diskFillCmd.Flags().Float64Var(&flags.diskUsage, "percentage", 95, "Specify up to which percentage the data volume should be used, must be greater than 0")
+	diskFillCmd.Flags().StringVar(&flags.diskFreeSpace, "freeSpace", "", "Specify how much space should be left free on the data volume, e.g. 1Gi")
+	diskFillCmd.MarkFlagsMutuallyExclusive("percentage", "freeSpace")
+	diskFillCmd.MarkFlagsMutuallyExclusive("partitionId", "nodeId")
+
+	diskCmd.AddCommand(diskThrottleCmd)
+	diskThrottleCmd.Flags().StringVar(&flags.diskReadBps, "readBps", "", "Specify the bytes per second which can be read, e.g. 10Mi")
+	diskThrottleCmd.Flags().StringVar(&flags.diskWriteBps, "writeBps", "", "Specify the bytes per second which can be written, e.g. 10Mi")
+	diskThrottleCmd.Flags().Int64Var(&flags.diskReadIops, "readIops", 0, "Specify the read operations per second")
+	diskThrottleCmd.Flags().Int64Var(&flags.diskWriteIops, "writeIops", 0, "Specify the write operations per second")
+	addRequiredDurationFlag(diskThrottleCmd, flags)
+	diskThrottleCmd.MarkFlagsOneRequired("readBps", "writeBps", "readIops", "writeIops")
+	diskThrottleCmd.MarkFlagsMutuallyExclusive("partitionId", "nodeId")
+
+	diskCmd.AddCommand(diskReleaseCmd)
+	diskReleaseCmd.Flags().BoolVar(&flags.all, "all", false, "Specify whether the data volumes of all brokers should be released")
+	diskReleaseCmd.MarkFlagsMutuallyExclusive("partitionId", "nodeId", "all")
+}
+
+// Parses the given quantity (e.g. 10Mi) into bytes, an empty quantity results in zero.
+func parseBytesFlag(quantity string) int64 {
+	if quantity == "" {
+		return 0
+	}
+	bytes, err := resource.ParseQuantity(quantity)
+	ensureNoError(err)
+	return bytes.Value()
+}
+
+// Returns the broker pod which is identified by the nodeId or by the partitionId and role flags.
+func getBrokerPodWithFlags(k8Client internal.K8Client, flags *Flags) *v1.Pod {
+	port, closeFn := k8Client.MustGatewayPortForward(0, 26500)
+	defer closeFn()
+
+	zbClient, err := internal.CreateZeebeClient(port, makeClientCredentials(flags))
+	ensureNoError(err)
+	defer zbClient.Close()
+
+	return getBrokerPod(k8Client, zbClient, flags.nodeId, flags.partitionId, flags.role)
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/camunda/zeebe-chaos/go-chaos/backend"
+	"github.com/spf13/cobra"
+)
+
+func AddDnsCommand(rootCmd *cobra.Command, flags *Flags) {
+	dns := &cobra.Command{
+		Use:   "dns",
+		Short: "Make dns lookups fail on Zeebe nodes",
+		Long: `Make dns lookups of the given hostnames fail on Zeebe nodes, uses sub-commands to select the node.
+The lookups can be dropped (time out), rejected (fail fast) or answered with NXDOMAIN. For the drop and reject mode,
+all lookups containing the hostname are affected, e.g. the headless service name. For the nxdomain mode, the hostname
+needs to be fully qualified, e.g. zeebe-broker-service.<namespace>.svc.cluster.local.
+The fault can be removed again with the connect command, or automatically via --duration.`,
+	}
+
+	dnsBroker := &cobra.Command{
+		Use:   "broker",
+		Short: "Make dns lookups fail on a Zeebe Broker",
+		Long:  `Make dns lookups fail on a Zeebe Broker. Broker can be identified via ID or partition and role.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := backend.InjectDnsFaultIntoBroker(flags.kubeConfigPath, flags.namespace, backend.Broker{
+				NodeId:      flags.nodeId,
+				PartitionId: flags.partitionId,
+				Role:        flags.role,
+			}, makeDnsFaultCfg(flags), makeClientCredentials(flags))
+			ensureNoError(err)
+		},
+	}
+
+	dnsGateway := &cobra.Command{
+		Use:   "gateway",
+		Short: "Make dns lookups fail on a Zeebe Gateway",
+		Long:  `Make dns lookups fail on a Zeebe Gateway, e.g. to break the broker discovery.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := backend.InjectDnsFaultIntoGateway(flags.kubeConfigPath, flags.namespace, makeDnsFaultCfg(flags))
+			ensureNoError(err)
+		},
+	}
+
+	rootCmd.AddCommand(dns)
+	dns.PersistentFlags().StringSliceVar(&flags.hostnames, "hostname", []string{}, "Specify the hostname(s) for which the lookups should fail, can be repeated")
+	dns.PersistentFlags().StringVar(&flags.dnsMode, "mode", "drop", "Specify how the lookups should fail [drop, reject, nxdomain]")
+	dns.MarkPersistentFlagRequired("hostname")
+
+	dns.AddCommand(dnsBroker)
+	dnsBroker.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	dnsBroker.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the Broker")
+	dnsBroker.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the partition id of the Broker")
+	dnsBroker.MarkFlagsMutuallyExclusive("partitionId", "nodeId")
+	addDurationFlag(dnsBroker, flags)
+
+	dns.AddCommand(dnsGateway)
+	addDurationFlag(dnsGateway, flags)
+}
+
+func makeDnsFaultCfg(flags *Flags) backend.DnsFaultCfg {
+	return backend.DnsFaultCfg{
+		Hostnames: flags.hostnames,
+		Mode:      flags.dnsMode,
+		Duration:  flags.duration,
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/spf13/cobra"
+)
+
+func AddFreezeCommand(rootCmd *cobra.Command, flags *Flags) {
+	freezeCmd := &cobra.Command{
+		Use:   "freeze",
+		Short: "Freezes a Zeebe node",
+		Long: `Freezes the process of a Zeebe node via SIGSTOP and resumes it via SIGCONT after the given duration.
+In contrast to a restart, the pod keeps running and connections stay open, peers only see timeouts (like on a long GC pause).`,
+	}
+
+	freezeBrokerCmd := &cobra.Command{
+		Use:   "broker",
+		Short: "Freezes a Zeebe broker",
+		Long:  `Freezes a Zeebe broker with a certain role and given partition, and resumes it after the given duration.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			if flags.all {
+				freezeBrokers(k8Client, flags.duration)
+			} else {
+				brokerPod := freezeBroker(k8Client, flags.nodeId, flags.partitionId, flags.role, flags.duration, makeClientCredentials(flags))
+				internal.LogInfo("Froze %s for %s", brokerPod, flags.duration)
+			}
+		},
+	}
+
+	rootCmd.AddCommand(freezeCmd)
+	freezeCmd.AddCommand(freezeBrokerCmd)
+	freezeBrokerCmd.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, INACTIVE]")
+	freezeBrokerCmd.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the id of the partition")
+	freezeBrokerCmd.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	freezeBrokerCmd.Flags().BoolVar(&flags.all, "all", false, "Specify whether all brokers should be frozen")
+	addRequiredDurationFlag(freezeBrokerCmd, flags)
+	freezeBrokerCmd.MarkFlagsMutuallyExclusive("partitionId", "nodeId", "all")
+	freezeBrokerCmd.MarkFlagsMutuallyExclusive("role", "all")
+}
+
+// Freezes a broker pod. Pod is identified either by nodeId or by partitionId and role.
+// Returns the broker which has been frozen
+func freezeBroker(k8Client internal.K8Client, nodeId int, partitionId int, role string, duration time.Duration, credentials *internal.ClientCredentials) string {
+	port, closeFn := k8Client.MustGatewayPortForward(0, 26500)
+	defer closeFn()
+
+	zbClient, err := internal.CreateZeebeClient(port, credentials)
+	ensureNoError(err)
+	defer zbClient.Close()
+
+	brokerPod := getBrokerPod(k8Client, zbClient, nodeId, partitionId, role)
+	err = internal.FreezeProcessOfPod(k8Client, brokerPod.Name, duration)
+	ensureNoError(err)
+
+	return brokerPod.Name
+}
+
+// Freezes all brokers in the current namespace.
+func freezeBrokers(k8Client internal.K8Client, duration time.Duration) {
+	brokerPodNames, err := k8Client.GetBrokerPodNames()
+	ensureNoError(err)
+
+	if len(brokerPodNames) <= 0 {
+		panic(errors.New(fmt.Sprintf("Expected to find a Zeebe broker in namespace %s, but none found", k8Client.GetCurrentNamespace())))
+	}
+
+	for _, brokerPodName := range brokerPodNames {
+		err = internal.FreezeProcessOfPod(k8Client, brokerPodName, duration)
+		ensureNoError(err)
+		internal.LogInfo("Froze %s for %s", brokerPodName, duration)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/spf13/cobra"
+)
+
+func AddKillCommand(rootCmd *cobra.Command, flags *Flags) {
+	killCmd := &cobra.Command{
+		Use:   "kill",
+		Short: "Kills the process of a Zeebe node",
+		Long: `Kills the process of a Zeebe node by sending a signal to it, without deleting the pod.
+The kubelet restarts the container on the same pod, which means the ip and the volume stay the same.`,
+	}
+
+	killBrokerCmd := &cobra.Command{
+		Use:   "broker",
+		Short: "Kills the process of a Zeebe broker",
+		Long:  `Kills the process of a Zeebe broker with a certain role and given partition, by sending the given signal to it.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			port, closeFn := k8Client.MustGatewayPortForward(0, 26500)
+			defer closeFn()
+
+			zbClient, err := internal.CreateZeebeClient(port, makeClientCredentials(flags))
+			ensureNoError(err)
+			defer zbClient.Close()
+
+			brokerPod := getBrokerPod(k8Client, zbClient, flags.nodeId, flags.partitionId, flags.role)
+			err = internal.SignalProcessOfPod(k8Client, brokerPod.Name, flags.signal)
+			ensureNoError(err)
+			internal.LogInfo("Sent signal %s to the process of %s", flags.signal, brokerPod.Name)
+		},
+	}
+
+	rootCmd.AddCommand(killCmd)
+	killCmd.AddCommand(killBrokerCmd)
+	killBrokerCmd.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, INACTIVE]")
+	killBrokerCmd.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the id of the partition")
+	killBrokerCmd.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	killBrokerCmd.Flags().StringVar(&flags.signal, "signal", "KILL", "Specify the signal which is sent to the process [KILL, TERM]")
+	killBrokerCmd.MarkFlagsMutuallyExclusive("partitionId", "nodeId")
+}
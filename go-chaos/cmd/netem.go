@@ -0,0 +1,141 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/camunda/zeebe-chaos/go-chaos/backend"
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/spf13/cobra"
+)
+
+func AddNetemCommands(rootCmd *cobra.Command, flags *Flags) {
+	delay := addNetemCommand(rootCmd, flags, "delay", "Delay network traffic", func() internal.NetemCfg {
+		return internal.NetemCfg{Delay: flags.networkDelay, Jitter: flags.networkJitter}
+	})
+	delay.PersistentFlags().StringVar(&flags.networkDelay, "delay", "100ms", "Specify the latency which should be added to each packet, e.g. 100ms")
+	delay.PersistentFlags().StringVar(&flags.networkJitter, "jitter", "", "Specify the variation of the added latency, e.g. 10ms")
+
+	loss := addNetemCommand(rootCmd, flags, "loss", "Drop network packets", func() internal.NetemCfg {
+		return internal.NetemCfg{Loss: flags.networkFaultPercentage}
+	})
+	loss.PersistentFlags().Float64Var(&flags.networkFaultPercentage, "percentage", 5, "Specify the percentage of packets which should be dropped")
+
+	duplicate := addNetemCommand(rootCmd, flags, "duplicate", "Duplicate network packets", func() internal.NetemCfg {
+		return internal.NetemCfg{Duplicate: flags.networkFaultPercentage}
+	})
+	duplicate.PersistentFlags().Float64Var(&flags.networkFaultPercentage, "percentage", 5, "Specify the percentage of packets which should be duplicated")
+
+	reorder := addNetemCommand(rootCmd, flags, "reorder", "Reorder network packets", func() internal.NetemCfg {
+		return internal.NetemCfg{Delay: flags.reorderDelay, Reorder: flags.reorderPercentage}
+	})
+	reorder.PersistentFlags().Float64Var(&flags.reorderPercentage, "percentage", 25, "Specify the percentage of packets which are sent immediately, all other packets are delayed")
+	reorder.PersistentFlags().StringVar(&flags.reorderDelay, "delay", "10ms", "Specify the latency which is added to the packets which are not sent immediately")
+
+	corrupt := addNetemCommand(rootCmd, flags, "corrupt", "Corrupt network packets", func() internal.NetemCfg {
+		return internal.NetemCfg{Corrupt: flags.networkFaultPercentage}
+	})
+	corrupt.PersistentFlags().Float64Var(&flags.networkFaultPercentage, "percentage", 5, "Specify the percentage of packets in which a random bit should be flipped")
+}
+
+// Adds a command with the given name, which applies the netem configuration returned by netemCfg on the traffic
+// between brokers (sub-command brokers) or between gateway and broker(s) (sub-command gateway).
+// The netemCfg function is called when the command is executed, such that the flags are already parsed.
+// Returns the created command, to allow to add specific flags.
+func addNetemCommand(rootCmd *cobra.Command, flags *Flags, use string, description string, netemCfg func() internal.NetemCfg) *cobra.Command {
+	netemCmd := &cobra.Command{
+		Use:   use,
+		Short: fmt.Sprintf("%s between Zeebe nodes", description),
+		Long: fmt.Sprintf(`%s between Zeebe nodes, uses sub-commands to target the traffic between brokers or between gateway and brokers.
+The fault is applied via tc/netem and can be removed again with the connect command, or automatically via --duration.`, description),
+	}
+
+	netemBrokers := &cobra.Command{
+		Use:   "brokers",
+		Short: fmt.Sprintf("%s between Zeebe Brokers", description),
+		Long:  fmt.Sprintf(`%s between Zeebe Brokers with a given partition and role.`, description),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := backend.NetemBroker(flags.kubeConfigPath, flags.namespace, backend.NetemBrokerCfg{
+				Broker1Cfg: backend.Broker{
+					NodeId:      flags.broker1NodeId,
+					PartitionId: flags.broker1PartitionId,
+					Role:        flags.broker1Role,
+				},
+				Broker2Cfg: backend.Broker{
+					NodeId:      flags.broker2NodeId,
+					PartitionId: flags.broker2PartitionId,
+					Role:        flags.broker2Role,
+				},
+				OneDirection: flags.oneDirection,
+				Netem:        netemCfg(),
+				Duration:     flags.duration,
+			},
+				makeClientCredentials(flags),
+			)
+			ensureNoError(err)
+		},
+	}
+
+	netemGateway := &cobra.Command{
+		Use:   "gateway",
+		Short: fmt.Sprintf("%s of Zeebe Gateway", description),
+		Long:  fmt.Sprintf(`%s between Zeebe Gateway and Broker with a given partition and role.`, description),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := backend.NetemGateway(flags.kubeConfigPath, flags.namespace, backend.NetemGatewayCfg{
+				OneDirection: flags.oneDirection,
+				ToAll:        flags.disconnectToAll,
+				BrokerCfg: backend.Broker{
+					Role:        flags.role,
+					PartitionId: flags.partitionId,
+					NodeId:      flags.nodeId,
+				},
+				Netem:    netemCfg(),
+				Duration: flags.duration,
+			}, makeClientCredentials(flags))
+			ensureNoError(err)
+		},
+	}
+
+	rootCmd.AddCommand(netemCmd)
+
+	// brokers
+	netemCmd.AddCommand(netemBrokers)
+	// broker 1
+	netemBrokers.Flags().StringVar(&flags.broker1Role, "broker1Role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the first Broker")
+	netemBrokers.Flags().IntVar(&flags.broker1PartitionId, "broker1PartitionId", 1, "Specify the partition id of the first Broker")
+	netemBrokers.Flags().IntVar(&flags.broker1NodeId, "broker1NodeId", -1, "Specify the nodeId of the first Broker")
+	netemBrokers.MarkFlagsMutuallyExclusive("broker1PartitionId", "broker1NodeId")
+	// broker 2
+	netemBrokers.Flags().StringVar(&flags.broker2Role, "broker2Role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the second Broker")
+	netemBrokers.Flags().IntVar(&flags.broker2PartitionId, "broker2PartitionId", 2, "Specify the partition id of the second Broker")
+	netemBrokers.Flags().IntVar(&flags.broker2NodeId, "broker2NodeId", -1, "Specify the nodeId of the second Broker")
+	netemBrokers.MarkFlagsMutuallyExclusive("broker2PartitionId", "broker2NodeId")
+	// general
+	netemBrokers.Flags().BoolVar(&flags.oneDirection, "one-direction", false, "Specify whether the fault should be applied only in one direction (asymmetric)")
+	addDurationFlag(netemBrokers, flags)
+
+	// gateway
+	netemCmd.AddCommand(netemGateway)
+	netemGateway.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	netemGateway.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the Broker")
+	netemGateway.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the partition id of the Broker")
+	netemGateway.Flags().BoolVar(&flags.oneDirection, "one-direction", false, "Specify whether the fault should be applied only in one direction (asymmetric)")
+	netemGateway.Flags().BoolVar(&flags.disconnectToAll, "all", false, "Specify whether the fault should be applied on the traffic to all brokers")
+	netemGateway.MarkFlagsMutuallyExclusive("all", "partitionId", "nodeId")
+	addDurationFlag(netemGateway, flags)
+
+	return netemCmd
+}
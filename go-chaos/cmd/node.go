@@ -0,0 +1,71 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/spf13/cobra"
+)
+
+func AddNodeCommand(rootCmd *cobra.Command, flags *Flags) {
+	nodeCmd := &cobra.Command{
+		Use:   "node",
+		Short: "Simulate maintenance of the Kubernetes nodes hosting Zeebe",
+		Long:  `Simulate maintenance of the Kubernetes nodes hosting Zeebe, by draining and uncordoning them.`,
+	}
+
+	nodeDrainCmd := &cobra.Command{
+		Use:   "drain",
+		Short: "Drain the Kubernetes node hosting a Zeebe Broker",
+		Long: `Drain the Kubernetes node hosting a Zeebe Broker. The node is cordoned, and the pods of the namespace running on it are evicted via the Eviction API.
+Only pods of the current namespace are evicted, pods of other namespaces keep running on the node (unlike kubectl drain).
+The evictions respect the PodDisruptionBudgets, blocked evictions are retried. The node can be uncordoned again with the uncordon command,
+nodes which have already been cordoned before are not uncordoned.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			pod, err := internal.GetBrokerPodForNodeId(k8Client, int32(flags.nodeId))
+			ensureNoError(err)
+
+			nodeName, err := k8Client.GetNodeOfPod(pod.Name)
+			ensureNoError(err)
+
+			evictedPods, err := k8Client.DrainNode(nodeName)
+			ensureNoError(err)
+			internal.LogInfo("Drained node %s, evicted pods %v", nodeName, evictedPods)
+		},
+	}
+
+	nodeUncordonCmd := &cobra.Command{
+		Use:   "uncordon",
+		Short: "Uncordon the Kubernetes nodes drained by zbchaos",
+		Long:  `Uncordon the Kubernetes nodes drained by zbchaos, such that pods can be scheduled on them again.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			uncordonedNodes, err := k8Client.UncordonDrainedNodes()
+			ensureNoError(err)
+			internal.LogInfo("Uncordoned nodes %v", uncordonedNodes)
+		},
+	}
+
+	rootCmd.AddCommand(nodeCmd)
+	nodeCmd.AddCommand(nodeDrainCmd)
+	nodeDrainCmd.Flags().IntVar(&flags.nodeId, "nodeId", 0, "Specify the nodeId of the Broker, whose Kubernetes node should be drained")
+	nodeDrainCmd.MarkFlagRequired("nodeId")
+	nodeCmd.AddCommand(nodeUncordonCmd)
+}
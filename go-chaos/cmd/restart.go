@@ -15,10 +15,25 @@
 package cmd
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
 	"github.com/spf13/cobra"
 )
 
+// The orders in which the brokers are restarted on a rolling restart
+const (
+	// brokers leading the fewest partitions are restarted first, leaders last
+	restartOrderFollowersFirst = "followers-first"
+	// brokers are restarted by ascending node id
+	restartOrderNodeId = "nodeId"
+)
+
+// How long a rolling restart waits for each broker to be ready and the partitions to be healthy
+const rollingRestartTimeout = 10 * time.Minute
+
 func AddRestartCmd(rootCmd *cobra.Command, flags *Flags) {
 	restartCmd := &cobra.Command{
 		Use:   "restart",
@@ -27,61 +42,175 @@ func AddRestartCmd(rootCmd *cobra.Command, flags *Flags) {
 	}
 
 	restartBrokerCmd := &cobra.Command{
-		Use:   "broker",
-		Short: "Restarts a Zeebe broker",
-		Long:  `Restarts a Zeebe broker with a certain role and given partition.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Use:           "broker",
+		Short:         "Restarts a Zeebe broker",
+		Long:          `Restarts a Zeebe broker with a certain role and given partition.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			k8Client, err := createK8ClientWithFlags(flags)
 			ensureNoError(err)
 			if flags.all {
-				restartBrokers(k8Client, "restart", nil)
-			} else {
-				brokerPod := restartBroker(k8Client, flags.nodeId, flags.partitionId, flags.role, nil, makeClientCredentials(flags))
-				internal.LogInfo("Restarted %s", brokerPod)
+				return restartBrokers(k8Client, "restart", nil, flags.evict)
+			}
+			brokerPod, err := restartBroker(k8Client, flags.nodeId, flags.partitionId, flags.role, nil, flags.evict, makeClientCredentials(flags))
+			if err != nil {
+				return err
+			}
+			internal.LogInfo("Restarted %s", brokerPod)
+			return nil
+		},
+	}
+
+	restartClusterCmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Restarts all Zeebe brokers",
+		Long: `Restarts all Zeebe brokers of the cluster. With --rolling the brokers are restarted one by one, like on an upgrade.
+After each restart it is awaited that the broker is ready and all partitions are healthy, before the next broker is restarted.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			if !flags.rolling {
+				return restartBrokers(k8Client, "restart", nil, flags.evict)
+			}
+
+			if flags.restartOrder != restartOrderFollowersFirst && flags.restartOrder != restartOrderNodeId {
+				panic(fmt.Errorf("expected order to be one of [%s, %s], but got '%s'", restartOrderFollowersFirst, restartOrderNodeId, flags.restartOrder))
 			}
+			return rollingRestart(k8Client, flags.restartOrder, flags.evict, makeClientCredentials(flags))
 		},
 	}
 
 	restartGatewayCmd := &cobra.Command{
-		Use:   "gateway",
-		Short: "Restarts a Zeebe gateway",
-		Long:  `Restarts a Zeebe gateway.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Use:           "gateway",
+		Short:         "Restarts a Zeebe gateway",
+		Long:          `Restarts a Zeebe gateway.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			k8Client, err := createK8ClientWithFlags(flags)
 			ensureNoError(err)
 
 			if flags.all {
-				restartGateways(k8Client, "restart", nil)
-			} else {
-				gatewayPod := restartGateway(k8Client, nil)
-				internal.LogInfo("Restarted %s", gatewayPod)
+				return restartGateways(k8Client, "restart", nil, flags.evict)
 			}
+			gatewayPod, err := restartGateway(k8Client, nil, flags.evict)
+			if err != nil {
+				return err
+			}
+			internal.LogInfo("Restarted %s", gatewayPod)
+			return nil
 		},
 	}
 
 	restartWorkerCmd := &cobra.Command{
-		Use:   "worker",
-		Short: "Restart a Zeebe worker",
-		Long:  `Restart a Zeebe worker.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Use:           "worker",
+		Short:         "Restart a Zeebe worker",
+		Long:          `Restart a Zeebe worker.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			k8Client, err := createK8ClientWithFlags(flags)
 			ensureNoError(err)
-			restartWorker(k8Client, flags.all, "Restarted", nil)
+			return restartWorker(k8Client, flags.all, "Restarted", nil, flags.evict)
 		},
 	}
 
 	rootCmd.AddCommand(restartCmd)
+	restartCmd.PersistentFlags().BoolVar(&flags.evict, "evict", false, "Specify whether the pods should be evicted via the Eviction API, which respects the PodDisruptionBudgets. Exits with code 3 if an eviction is blocked")
 	restartCmd.AddCommand(restartBrokerCmd)
-	restartBrokerCmd.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, INACTIVE]")
-	restartBrokerCmd.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the id of the partition")
-	restartBrokerCmd.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	restartBrokerCmd.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, INACTIVE, RANDOM]")
+	targetIdVar(restartBrokerCmd.Flags(), &flags.partitionId, "partitionId", 1, "Specify the id of the partition")
+	targetIdVar(restartBrokerCmd.Flags(), &flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
 	restartBrokerCmd.Flags().BoolVar(&flags.all, "all", false, "Specify whether all brokers should be restarted")
 	restartBrokerCmd.MarkFlagsMutuallyExclusive("partitionId", "nodeId", "all")
 	restartBrokerCmd.MarkFlagsMutuallyExclusive("role", "all")
 
+	restartCmd.AddCommand(restartClusterCmd)
+	restartClusterCmd.Flags().BoolVar(&flags.rolling, "rolling", false, "Specify whether the brokers should be restarted one by one, awaiting a healthy cluster after each restart")
+	restartClusterCmd.Flags().StringVar(&flags.restartOrder, "order", restartOrderFollowersFirst, "Specify the order of a rolling restart [followers-first, nodeId]")
+
 	restartCmd.AddCommand(restartGatewayCmd)
 	restartGatewayCmd.Flags().BoolVar(&flags.all, "all", false, "Specify whether all gateways should be restarted")
 
 	restartCmd.AddCommand(restartWorkerCmd)
 	restartWorkerCmd.Flags().BoolVar(&flags.all, "all", false, "Specify whether all workers should be restarted")
 }
+
+// Restarts the brokers one by one, the next broker is chosen after each restart based on the current topology.
+// After each restart it is awaited that the broker is recreated and ready, and that all partitions are healthy.
+// Returns an evictionBlockedError if the eviction of a broker is blocked.
+func rollingRestart(k8Client internal.K8Client, order string, evict bool, credentials *internal.ClientCredentials) error {
+	topology := awaitHealthyTopology(k8Client, credentials)
+
+	var remaining []int
+	for nodeId := 0; nodeId < int(topology.ClusterSize); nodeId++ {
+		remaining = append(remaining, nodeId)
+	}
+
+	var restartedPods []string
+	for len(remaining) > 0 {
+		nodeId := nextBrokerToRestart(topology, remaining, order)
+		for i, remainingNodeId := range remaining {
+			if remainingNodeId == nodeId {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+
+		brokerPod, err := internal.GetBrokerPodForNodeId(k8Client, int32(nodeId))
+		ensureNoError(err)
+		err = restartPod(k8Client, brokerPod.Name, nil, evict)
+		if err = checkRestarted(err, brokerPod.Name, restartedPods); err != nil {
+			return err
+		}
+		internal.LogInfo("Restarted %s", brokerPod.Name)
+		restartedPods = append(restartedPods, brokerPod.Name)
+
+		err = k8Client.AwaitPodRecreation(brokerPod.Name, brokerPod.UID, rollingRestartTimeout)
+		ensureNoError(err)
+		topology = awaitHealthyTopology(k8Client, credentials)
+		internal.LogInfo("%s is ready and all partitions are healthy", brokerPod.Name)
+	}
+	return nil
+}
+
+// Awaits a healthy topology, a new port forward is used on each call since the gateway might have been restarted
+func awaitHealthyTopology(k8Client internal.K8Client, credentials *internal.ClientCredentials) *pb.TopologyResponse {
+	port, closeFn := k8Client.MustGatewayPortForward(0, 26500)
+	defer closeFn()
+
+	zbClient, err := internal.CreateZeebeClient(port, credentials)
+	ensureNoError(err)
+	defer zbClient.Close()
+
+	topology, err := internal.AwaitHealthyTopology(zbClient, rollingRestartTimeout)
+	ensureNoError(err)
+	return topology
+}
+
+// Returns the broker of the remaining brokers, which should be restarted next. With the followers-first order the
+// broker leading the fewest partitions is chosen, otherwise the broker with the lowest node id.
+func nextBrokerToRestart(topology *pb.TopologyResponse, remaining []int, order string) int {
+	leaderships := map[int]int{}
+	if order == restartOrderFollowersFirst {
+		for _, broker := range topology.Brokers {
+			for _, partition := range broker.Partitions {
+				if partition.Role == pb.Partition_LEADER {
+					leaderships[int(broker.NodeId)]++
+				}
+			}
+		}
+	}
+
+	next := remaining[0]
+	for _, nodeId := range remaining[1:] {
+		if leaderships[nodeId] < leaderships[next] || (leaderships[nodeId] == leaderships[next] && nodeId < next) {
+			next = nodeId
+		}
+	}
+	return next
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRollingRestartTopology() *pb.TopologyResponse {
+	return &pb.TopologyResponse{
+		ClusterSize: 3,
+		Brokers: []*pb.BrokerInfo{
+			{NodeId: 0, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_LEADER}, {PartitionId: 2, Role: pb.Partition_LEADER}}},
+			{NodeId: 1, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_FOLLOWER}, {PartitionId: 3, Role: pb.Partition_LEADER}}},
+			{NodeId: 2, Partitions: []*pb.Partition{{PartitionId: 2, Role: pb.Partition_FOLLOWER}, {PartitionId: 3, Role: pb.Partition_FOLLOWER}}},
+		},
+	}
+}
+
+func Test_ShouldRestartFollowersFirst(t *testing.T) {
+	// given
+	topology := createRollingRestartTopology()
+
+	// when
+	first := nextBrokerToRestart(topology, []int{0, 1, 2}, restartOrderFollowersFirst)
+	second := nextBrokerToRestart(topology, []int{0, 1}, restartOrderFollowersFirst)
+
+	// then
+	assert.Equal(t, 2, first)
+	assert.Equal(t, 1, second)
+}
+
+func Test_ShouldRestartByNodeId(t *testing.T) {
+	// given
+	topology := createRollingRestartTopology()
+
+	// when
+	first := nextBrokerToRestart(topology, []int{2, 1, 0}, restartOrderNodeId)
+	second := nextBrokerToRestart(topology, []int{2, 1}, restartOrderNodeId)
+
+	// then
+	assert.Equal(t, 0, first)
+	assert.Equal(t, 1, second)
+}
@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -47,7 +48,26 @@ type Flags struct {
 	broker2NodeId      int
 
 	// deploy worker
-	pollingDelayMs int
+	pollingDelayMs       int
+	deployWorkerReplicas int32
+	workerJobType        string
+	completionDelay      time.Duration
+	failurePercentage    float64
+	bpmnErrorPercentage  float64
+	timeoutPercentage    float64
+	workerCpuRequest     string
+	workerMemoryRequest  string
+	workerCpuLimit       string
+	workerMemoryLimit    string
+	zbchaosImageTag      string
+
+	// benchmark worker
+	gatewayAddress string
+
+	// scale worker
+	workerReplicas int32
+	workerToZero   bool
+	workerRestore  bool
 
 	// backup
 	backupId string
@@ -55,17 +75,57 @@ type Flags struct {
 	// disconnect
 	oneDirection    bool
 	disconnectToAll bool
+	groups          []string
+	ports           []int
+	duration        time.Duration
+	hostnames       []string
+	dnsMode         string
+	signal          string
+	diskUsage       float64
+	diskFreeSpace   string
+	diskReadBps     string
+	diskWriteBps    string
+	diskReadIops    int64
+	diskWriteIops   int64
+
+	// netem
+	networkDelay           string
+	networkJitter          string
+	networkFaultPercentage float64
+	reorderDelay           string
+	reorderPercentage      float64
+
+	// throttle
+	bandwidthRate   string
+	bandwidthBurst  string
+	peerNodeIds     []int
+	peerPartitionId int
+	peerRole        string
 
 	// stress
 
-	cpuStress    bool
-	memoryStress bool
-	ioStress     bool
-	timeoutSec   string
+	cpuStress     bool
+	memoryStress  bool
+	ioStress      bool
+	timeoutSec    string
+	stressBackend string
+	cpuWorkers    int
+	cpuLoad       int
+	memoryWorkers int
+	memoryBytes   string
+	ioWorkers     int
 
 	// terminate
 
-	all bool
+	all             bool
+	brokerCount     int
+	replicas        string
+	awaitRecreation bool
+	evict           bool
+
+	// restart
+	rolling      bool
+	restartOrder string
 
 	// verify
 	version        int
@@ -86,7 +146,12 @@ type Flags struct {
 	partitionCount    int32
 
 	// dataloss
-	awaitReadiness bool
+	awaitReadiness   bool
+	corruptionTarget string
+	corruptionMode   string
+
+	// random target selection
+	seed int64
 
 	// client connection
 	authServer   string
@@ -115,6 +180,9 @@ func NewCmd() *cobra.Command {
 			internal.Verbosity = Verbose
 			internal.LogVerbose("Flags: %v", flags)
 			internal.JsonLogging = JsonLogging
+			if cmd.Flags().Changed("seed") {
+				internal.SetRandomSeed(flags.seed)
+			}
 			if JsonLogging {
 				internal.JsonLogger = log.With().Logger()
 			}
@@ -124,6 +192,9 @@ func NewCmd() *cobra.Command {
 	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&JsonLogging, "jsonLogging", "", false, "json logging output")
 	rootCmd.PersistentFlags().StringVar(&flags.kubeConfigPath, "kubeconfig", "", "path the the kube config that will be used")
+	rootCmd.PersistentFlags().StringVar(&internal.DebugImage, "debugImage", internal.ToolsDebugImage(Version), "the image of the debug containers, which are used to inject faults. Per default the image contains all tools, for other images (e.g. "+internal.FallbackDebugImage+") the tools are installed on each invocation")
+	rootCmd.PersistentFlags().BoolVar(&internal.DebugImageHasTools, "debugImageHasTools", false, "whether a custom debug image (e.g. a mirror of "+internal.ToolsDebugImageRepository+") already contains the tools to inject faults, such that they are not installed on each invocation")
+	rootCmd.PersistentFlags().Int64Var(&flags.seed, "seed", 0, "the seed for choosing random targets, e.g. --nodeId random. Per default a new seed is used, which is logged with each choice to replay a run")
 	rootCmd.PersistentFlags().StringVarP(&flags.namespace, "namespace", "n", "", "connect to the given namespace")
 	rootCmd.PersistentFlags().StringVarP(&DockerImageTag, "dockerImageTag", "", DockerImageTag, "use the given docker image tag for deployed resources, e.g. worker/starter")
 	// auth flags
@@ -140,14 +211,21 @@ func NewCmd() *cobra.Command {
 	AddDeployCmd(rootCmd, &flags)
 	AddDisconnectCommand(rootCmd, &flags)
 	AddExportingCmds(rootCmd, &flags)
+	AddNetemCommands(rootCmd, &flags)
 	AddPublishCmd(rootCmd, &flags)
 	AddRestartCmd(rootCmd, &flags)
 	AddStressCmd(rootCmd, &flags)
 	AddTerminateCommand(rootCmd, &flags)
+	AddThrottleCommand(rootCmd, &flags)
+	AddDnsCommand(rootCmd, &flags)
+	AddFreezeCommand(rootCmd, &flags)
+	AddKillCommand(rootCmd, &flags)
+	AddDiskCommand(rootCmd, &flags)
+	AddNodeCommand(rootCmd, &flags)
 	AddTopologyCmd(rootCmd, &flags)
 	AddVerifyCommands(rootCmd, &flags)
 	AddVersionCmd(rootCmd)
-	AddWorkerCmd(rootCmd)
+	AddWorkerCmd(rootCmd, &flags)
 	AddClusterCommands(rootCmd, &flags)
 
 	return rootCmd
@@ -160,6 +238,9 @@ func createK8ClientWithFlags(flags *Flags) (internal.K8Client, error) {
 func Execute() {
 	if err := NewCmd().Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		if errors.As(err, &evictionBlockedError{}) {
+			os.Exit(evictionBlockedExitCode)
+		}
 		os.Exit(1)
 	}
 }
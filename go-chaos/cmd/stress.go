@@ -17,6 +17,8 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/camunda/zeebe-chaos/go-chaos/internal"
 	"github.com/camunda/zeebe/clients/go/v8/pkg/zbc"
@@ -50,8 +52,8 @@ func AddStressCmd(rootCmd *cobra.Command, flags *Flags) {
 			pod := getBrokerPod(k8Client, zbClient, flags.nodeId, flags.partitionId, flags.role)
 			internal.LogInfo("Put stress on %s", pod.Name)
 
-			stressType := internal.StressType{CpuStress: flags.cpuStress, IoStress: flags.ioStress, MemStress: flags.memoryStress}
-			err = internal.PutStressOnPod(k8Client, flags.timeoutSec, pod.Name, "zeebe", stressType)
+			stressType := stressTypeFromFlags(flags)
+			err = internal.PutStressOnPod(k8Client, stressTimeout(flags), pod.Name, "zeebe", stressType)
 			ensureNoError(err)
 		},
 	}
@@ -68,31 +70,114 @@ func AddStressCmd(rootCmd *cobra.Command, flags *Flags) {
 			pod := getGatewayPod(k8Client)
 			internal.LogInfo("Put stress on %s", pod.Name)
 
-			stressType := internal.StressType{CpuStress: flags.cpuStress, IoStress: flags.ioStress, MemStress: flags.memoryStress}
-			err = internal.PutStressOnPod(k8Client, flags.timeoutSec, pod.Name, "zeebe-gateway", stressType)
+			stressType := stressTypeFromFlags(flags)
+			err = internal.PutStressOnPod(k8Client, stressTimeout(flags), pod.Name, "zeebe-gateway", stressType)
 			ensureNoError(err)
 		},
 	}
 
+	stressStop := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the stress on a Zeebe node",
+		Long:  `Stop the stress on a Zeebe node, which has been started via the stress command. Stopping the stress on a node without stress has no effect.`,
+	}
+
+	stressStopBroker := &cobra.Command{
+		Use:   "broker",
+		Short: "Stop the stress on a Zeebe Broker",
+		Long:  `Stop the stress on a Zeebe Broker. Broker can be identified via ID or partition and role.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			port, closeFn := k8Client.MustGatewayPortForward(0, 26500)
+			defer closeFn()
+
+			zbClient, err := internal.CreateZeebeClient(port, makeClientCredentials(flags))
+			ensureNoError(err)
+			defer zbClient.Close()
+
+			pod := getBrokerPod(k8Client, zbClient, flags.nodeId, flags.partitionId, flags.role)
+			output, err := internal.StopStressOnPod(k8Client, pod.Name, "zeebe")
+			ensureNoError(err)
+			internal.LogInfo("Stopped stress on %s: %s", pod.Name, output)
+		},
+	}
+
+	stressStopGateway := &cobra.Command{
+		Use:   "gateway",
+		Short: "Stop the stress on a Zeebe Gateway",
+		Long:  `Stop the stress on a Zeebe Gateway.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			pod := getGatewayPod(k8Client)
+			output, err := internal.StopStressOnPod(k8Client, pod.Name, "zeebe-gateway")
+			ensureNoError(err)
+			internal.LogInfo("Stopped stress on %s: %s", pod.Name, output)
+		},
+	}
+
 	rootCmd.AddCommand(stress)
 	stress.PersistentFlags().BoolVar(&flags.cpuStress, "cpu", true, "Specify whether CPU stress should put on the node")
 	stress.PersistentFlags().BoolVar(&flags.memoryStress, "memory", false, "Specify whether memory stress should put on the node")
 	stress.PersistentFlags().BoolVar(&flags.ioStress, "io", false, "Specify whether io stress should put on the node")
+	stress.PersistentFlags().StringVar(&flags.stressBackend, "backend", internal.StressBackendStress, "Specify the tool which puts the stress on the node [stress, stress-ng]")
+	stress.PersistentFlags().IntVar(&flags.cpuWorkers, "cpuWorkers", internal.DefaultCpuWorkers, "Specify the number of CPU workers, with stress-ng 0 starts one worker per CPU")
+	stress.PersistentFlags().IntVar(&flags.cpuLoad, "cpuLoad", 100, "Specify the load of each CPU worker in percent, only supported by stress-ng")
+	stress.PersistentFlags().IntVar(&flags.memoryWorkers, "memoryWorkers", internal.DefaultMemoryWorkers, "Specify the number of memory workers")
+	stress.PersistentFlags().StringVar(&flags.memoryBytes, "memoryBytes", "", "Specify the bytes which are allocated per memory worker, e.g. 512M. Default: 256M")
+	stress.PersistentFlags().IntVar(&flags.ioWorkers, "ioWorkers", internal.DefaultIoWorkers, "Specify the number of io workers")
 	stressBroker.PersistentFlags().StringVar(&flags.timeoutSec, "timeout", "30", "Specify how long the stress should be executed in seconds. Default: 30")
+	stress.PersistentFlags().DurationVar(&flags.duration, "duration", 0, "Specify how long the stress should be executed (e.g. 5m), alternative to the timeout")
 
 	// stress brokers
 	stress.AddCommand(stressBroker)
+	stressBroker.MarkFlagsMutuallyExclusive("timeout", "duration")
 
-	stressBroker.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
-	stressBroker.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the Broker")
-	stressBroker.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the partition id of the Broker")
+	targetIdVar(stressBroker.Flags(), &flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	stressBroker.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, RANDOM] of the Broker")
+	targetIdVar(stressBroker.Flags(), &flags.partitionId, "partitionId", 1, "Specify the partition id of the Broker")
 
 	stress.AddCommand(stressGateway)
+
+	// stop stress
+	stress.AddCommand(stressStop)
+	stressStop.AddCommand(stressStopBroker)
+	stressStopBroker.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	stressStopBroker.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the Broker")
+	stressStopBroker.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the partition id of the Broker")
+	stressStopBroker.MarkFlagsMutuallyExclusive("partitionId", "nodeId")
+	stressStop.AddCommand(stressStopGateway)
+}
+
+func stressTypeFromFlags(flags *Flags) internal.StressType {
+	return internal.StressType{
+		CpuStress:  flags.cpuStress,
+		IoStress:   flags.ioStress,
+		MemStress:  flags.memoryStress,
+		Backend:    flags.stressBackend,
+		CpuWorkers: flags.cpuWorkers,
+		CpuLoad:    flags.cpuLoad,
+		MemWorkers: flags.memoryWorkers,
+		MemBytes:   flags.memoryBytes,
+		IoWorkers:  flags.ioWorkers,
+	}
+}
+
+// Returns the timeout of the stress in seconds, the duration takes precedence over the timeout if it is set.
+func stressTimeout(flags *Flags) string {
+	if flags.duration > 0 {
+		return strconv.Itoa(int(math.Ceil(flags.duration.Seconds())))
+	}
+	return flags.timeoutSec
 }
 
 func getBrokerPod(k8Client internal.K8Client, zbClient zbc.Client, brokerNodeId int, brokerPartitionId int, brokerRole string) *v1.Pod {
 	var brokerPod *v1.Pod
-	var err error
+	brokerNodeId, brokerPartitionId, brokerRole, err := internal.ResolveRandomTarget(zbClient, brokerNodeId, brokerPartitionId, brokerRole)
+	ensureNoError(err)
 	if brokerNodeId >= 0 {
 		brokerPod, err = internal.GetBrokerPodForNodeId(k8Client, int32(brokerNodeId))
 		ensureNoError(err)
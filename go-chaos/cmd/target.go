@@ -0,0 +1,60 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/spf13/pflag"
+)
+
+const randomTarget = "random"
+
+// targetIdValue is a node or partition id flag, which accepts either a number or "random"
+type targetIdValue int
+
+func (v *targetIdValue) Set(value string) error {
+	if strings.EqualFold(value, randomTarget) {
+		*v = internal.RandomId
+		return nil
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("expected a number or '%s', but got '%s'", randomTarget, value)
+	}
+	*v = targetIdValue(id)
+	return nil
+}
+
+func (v *targetIdValue) String() string {
+	if int(*v) == internal.RandomId {
+		return randomTarget
+	}
+	return strconv.Itoa(int(*v))
+}
+
+func (v *targetIdValue) Type() string {
+	return "int|random"
+}
+
+// Adds a node or partition id flag, which accepts "random" to choose a random target
+func targetIdVar(flagSet *pflag.FlagSet, p *int, name string, value int, usage string) {
+	*p = value
+	flagSet.Var((*targetIdValue)(p), name, usage+", or random")
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldParseTargetIdFlag(t *testing.T) {
+	// given
+	var nodeId, partitionId int
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	targetIdVar(flagSet, &nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	targetIdVar(flagSet, &partitionId, "partitionId", 1, "Specify the id of the partition")
+
+	// when
+	err := flagSet.Parse([]string{"--nodeId", "random", "--partitionId", "3"})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, internal.RandomId, nodeId)
+	assert.Equal(t, 3, partitionId)
+	assert.Equal(t, "random", flagSet.Lookup("nodeId").Value.String())
+}
+
+func Test_ShouldKeepDefaultOfTargetIdFlag(t *testing.T) {
+	// given
+	var nodeId int
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	targetIdVar(flagSet, &nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+
+	// when
+	err := flagSet.Parse([]string{})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, -1, nodeId)
+	assert.Equal(t, "-1", flagSet.Lookup("nodeId").DefValue)
+}
+
+func Test_ShouldRejectInvalidTargetId(t *testing.T) {
+	// given
+	var nodeId int
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	targetIdVar(flagSet, &nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+
+	// when
+	err := flagSet.Parse([]string{"--nodeId", "any"})
+
+	// then
+	require.Error(t, err)
+}
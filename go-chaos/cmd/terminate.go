@@ -17,11 +17,18 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
 	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
 )
 
+// Exit code of terminate and restart, if the eviction of a pod is blocked by a PodDisruptionBudget
+const evictionBlockedExitCode = 3
+
 func AddTerminateCommand(rootCmd *cobra.Command, flags *Flags) {
 	terminateCmd := &cobra.Command{
 		Use:   "terminate",
@@ -30,62 +37,138 @@ func AddTerminateCommand(rootCmd *cobra.Command, flags *Flags) {
 	}
 
 	terminateBrokerCmd := &cobra.Command{
-		Use:   "broker",
-		Short: "Terminates a Zeebe broker",
-		Long:  `Terminates a Zeebe broker with a certain role and given partition.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Use:           "broker",
+		Short:         "Terminates a Zeebe broker",
+		Long:          `Terminates a Zeebe broker with a certain role and given partition.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			k8Client, err := createK8ClientWithFlags(flags)
 			ensureNoError(err)
 			gracePeriodSec := int64(0)
 			if flags.all {
-				restartBrokers(k8Client, "terminate", &gracePeriodSec)
-			} else {
-				brokerPod := restartBroker(k8Client, flags.nodeId, flags.partitionId, flags.role, &gracePeriodSec, makeClientCredentials(flags))
-				internal.LogInfo("Terminated %s", brokerPod)
+				return restartBrokers(k8Client, "terminate", &gracePeriodSec, flags.evict)
+			}
+			brokerPod, err := restartBroker(k8Client, flags.nodeId, flags.partitionId, flags.role, &gracePeriodSec, flags.evict, makeClientCredentials(flags))
+			if err != nil {
+				return err
 			}
+			internal.LogInfo("Terminated %s", brokerPod)
+			return nil
+		},
+	}
+
+	terminateBrokersCmd := &cobra.Command{
+		Use:   "brokers",
+		Short: "Terminates multiple Zeebe brokers at once",
+		Long: `Terminates multiple Zeebe brokers at the same time, either a given count of random brokers or replicas of a given partition.
+Allows to verify the unavailability and recovery of a partition, e.g. when exactly the majority of its replicas is lost.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			port, closeFn := k8Client.MustGatewayPortForward(0, 26500)
+			defer closeFn()
+
+			zbClient, err := internal.CreateZeebeClient(port, makeClientCredentials(flags))
+			ensureNoError(err)
+			defer zbClient.Close()
+
+			topology, err := internal.GetTopology(zbClient)
+			ensureNoError(err)
+
+			nodeIds, err := chooseBrokersToTerminate(topology, flags.brokerCount, flags.partitionId, flags.replicas)
+			ensureNoError(err)
+
+			// look up all pods before terminating them, since a terminated pod might be missing from the pod list until it is
+			// recreated, and its original UID is needed to await the recreation
+			var brokerPods []*v1.Pod
+			for _, nodeId := range nodeIds {
+				brokerPod, err := internal.GetBrokerPodForNodeId(k8Client, int32(nodeId))
+				ensureNoError(err)
+				brokerPods = append(brokerPods, brokerPod)
+			}
+
+			gracePeriodSec := int64(0)
+			var terminatedPods []string
+			for _, brokerPod := range brokerPods {
+				err = restartPod(k8Client, brokerPod.Name, &gracePeriodSec, flags.evict)
+				if err = checkRestarted(err, brokerPod.Name, terminatedPods); err != nil {
+					return err
+				}
+				internal.LogInfo("Terminated %s", brokerPod.Name)
+				terminatedPods = append(terminatedPods, brokerPod.Name)
+			}
+
+			if flags.awaitRecreation {
+				for _, brokerPod := range brokerPods {
+					err = k8Client.AwaitPodRecreation(brokerPod.Name, brokerPod.UID, 10*time.Minute)
+					ensureNoError(err)
+					internal.LogInfo("%s is ready again", brokerPod.Name)
+				}
+			}
+			return nil
 		},
 	}
 
 	terminateGatewayCmd := &cobra.Command{
-		Use:   "gateway",
-		Short: "Terminates a Zeebe gateway",
-		Long:  `Terminates a Zeebe gateway.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Use:           "gateway",
+		Short:         "Terminates a Zeebe gateway",
+		Long:          `Terminates a Zeebe gateway.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			k8Client, err := createK8ClientWithFlags(flags)
 			ensureNoError(err)
 			gracePeriodSec := int64(0)
 
 			if flags.all {
-				restartGateways(k8Client, "terminate", &gracePeriodSec)
-			} else {
-				gatewayPod := restartGateway(k8Client, &gracePeriodSec)
-				internal.LogInfo("Restarted %s", gatewayPod)
+				return restartGateways(k8Client, "terminate", &gracePeriodSec, flags.evict)
+			}
+			gatewayPod, err := restartGateway(k8Client, &gracePeriodSec, flags.evict)
+			if err != nil {
+				return err
 			}
+			internal.LogInfo("Restarted %s", gatewayPod)
+			return nil
 		},
 	}
 
 	terminateWorkerCmd := &cobra.Command{
-		Use:   "worker",
-		Short: "Terminates a Zeebe worker",
-		Long:  `Terminates a Zeebe worker.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Use:           "worker",
+		Short:         "Terminates a Zeebe worker",
+		Long:          `Terminates a Zeebe worker.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			k8Client, err := createK8ClientWithFlags(flags)
 			ensureNoError(err)
 			gracePeriodSec := int64(0)
-			restartWorker(k8Client, flags.all, "Terminated", &gracePeriodSec)
+			return restartWorker(k8Client, flags.all, "Terminated", &gracePeriodSec, flags.evict)
 		},
 	}
 
 	rootCmd.AddCommand(terminateCmd)
+	terminateCmd.PersistentFlags().BoolVar(&flags.evict, "evict", false, "Specify whether the pods should be evicted via the Eviction API, which respects the PodDisruptionBudgets. Exits with code 3 if an eviction is blocked")
 
 	terminateCmd.AddCommand(terminateBrokerCmd)
-	terminateBrokerCmd.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER]")
-	terminateBrokerCmd.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the id of the partition")
-	terminateBrokerCmd.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	terminateBrokerCmd.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER, RANDOM]")
+	targetIdVar(terminateBrokerCmd.Flags(), &flags.partitionId, "partitionId", 1, "Specify the id of the partition")
+	targetIdVar(terminateBrokerCmd.Flags(), &flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
 	terminateBrokerCmd.Flags().BoolVar(&flags.all, "all", false, "Specify whether all brokers should be terminated")
 	terminateBrokerCmd.MarkFlagsMutuallyExclusive("partitionId", "nodeId", "all")
 	terminateBrokerCmd.MarkFlagsMutuallyExclusive("role", "all")
 
+	terminateCmd.AddCommand(terminateBrokersCmd)
+	terminateBrokersCmd.Flags().IntVar(&flags.brokerCount, "count", 0, "Specify the count of random brokers which should be terminated")
+	terminateBrokersCmd.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the id of the partition whose replicas should be terminated")
+	terminateBrokersCmd.Flags().StringVar(&flags.replicas, "replicas", "majority", "Specify how many replicas of the partition should be terminated [majority, all, or a number]")
+	terminateBrokersCmd.Flags().BoolVar(&flags.awaitRecreation, "awaitReadiness", false, "If true wait until the terminated brokers are ready again")
+	terminateBrokersCmd.MarkFlagsMutuallyExclusive("count", "partitionId")
+	terminateBrokersCmd.MarkFlagsMutuallyExclusive("count", "replicas")
+
 	terminateCmd.AddCommand(terminateGatewayCmd)
 	terminateGatewayCmd.Flags().BoolVar(&flags.all, "all", false, "Specify whether all gateways should be terminated")
 
@@ -94,9 +177,9 @@ func AddTerminateCommand(rootCmd *cobra.Command, flags *Flags) {
 }
 
 // Restart a broker pod. Pod is identified either by nodeId or by partitionId and role.
-// GracePeriod (in second) can be nil, which would mean using K8 default.
-// Returns the broker which has been restarted
-func restartBroker(k8Client internal.K8Client, nodeId int, partitionId int, role string, gracePeriod *int64, credentials *internal.ClientCredentials) string {
+// GracePeriod (in second) can be nil, which would mean using K8 default. If evict is set, the pod is evicted instead.
+// Returns the broker which has been restarted, or an evictionBlockedError if the eviction is blocked
+func restartBroker(k8Client internal.K8Client, nodeId int, partitionId int, role string, gracePeriod *int64, evict bool, credentials *internal.ClientCredentials) (string, error) {
 	port, closeFn := k8Client.MustGatewayPortForward(0, 26500)
 	defer closeFn()
 
@@ -105,15 +188,102 @@ func restartBroker(k8Client internal.K8Client, nodeId int, partitionId int, role
 	defer zbClient.Close()
 
 	brokerPod := getBrokerPod(k8Client, zbClient, nodeId, partitionId, role)
-	err = k8Client.RestartPodWithGracePeriod(brokerPod.Name, gracePeriod)
+	err = restartPod(k8Client, brokerPod.Name, gracePeriod, evict)
+	if err = checkRestarted(err, brokerPod.Name, nil); err != nil {
+		return "", err
+	}
+
+	return brokerPod.Name, nil
+}
+
+// Deletes the given pod, such that it is restarted. If evict is set, the pod is evicted via the Eviction API instead,
+// which fails if a PodDisruptionBudget doesn't allow to evict the pod.
+func restartPod(k8Client internal.K8Client, podName string, gracePeriod *int64, evict bool) error {
+	if !evict {
+		return k8Client.RestartPodWithGracePeriod(podName, gracePeriod)
+	}
+
+	return k8Client.EvictPodWithGracePeriod(podName, gracePeriod)
+}
+
+// evictionBlockedError is returned by terminate and restart, if the eviction of a pod has been blocked by a
+// PodDisruptionBudget. Execute exits with evictionBlockedExitCode on it, after the deferred cleanups have run.
+type evictionBlockedError struct {
+	message string
+}
+
+func (e evictionBlockedError) Error() string {
+	return e.message
+}
+
+func (e evictionBlockedError) Unwrap() error {
+	return internal.ErrEvictionBlocked
+}
+
+// Returns an evictionBlockedError if the eviction of the given pod has been blocked by a PodDisruptionBudget, which
+// reports the pods that have already been restarted before. Other errors panic, like in ensureNoError.
+func checkRestarted(err error, podName string, restartedPods []string) error {
+	if errors.Is(err, internal.ErrEvictionBlocked) {
+		return evictionBlockedError{message: describeBlockedEviction(podName, restartedPods)}
+	}
 	ensureNoError(err)
+	return nil
+}
+
+func describeBlockedEviction(podName string, restartedPods []string) string {
+	if len(restartedPods) == 0 {
+		return fmt.Sprintf("The PodDisruptionBudget of pod %s doesn't allow to evict it, no pod has been restarted.", podName)
+	}
+	return fmt.Sprintf("The PodDisruptionBudget of pod %s doesn't allow to evict it, the pods %v have already been restarted.", podName, restartedPods)
+}
+
+// Returns the node ids of the brokers which should be terminated, either the given count of random brokers or random
+// replicas of the given partition.
+func chooseBrokersToTerminate(topology *pb.TopologyResponse, count int, partitionId int, replicas string) ([]int, error) {
+	if count > 0 {
+		var nodeIds []int
+		for nodeId := 0; nodeId < int(topology.ClusterSize); nodeId++ {
+			nodeIds = append(nodeIds, nodeId)
+		}
+		return internal.ChooseRandomNodeIds(nodeIds, count)
+	}
+
+	replicaCount, err := parseReplicaCount(replicas, int(topology.ReplicationFactor))
+	if err != nil {
+		return nil, err
+	}
+
+	var replicaNodeIds []int
+	for _, replica := range internal.GetPartitionReplicas(topology, partitionId) {
+		replicaNodeIds = append(replicaNodeIds, replica.NodeId)
+	}
+	// replicas of brokers which are down are not part of the topology, we can't terminate them (again)
+	if len(replicaNodeIds) < replicaCount {
+		return nil, fmt.Errorf("expected to terminate %d replicas of partition %d, but only %d replicas are part of the topology %v", replicaCount, partitionId, len(replicaNodeIds), replicaNodeIds)
+	}
+	return internal.ChooseRandomNodeIds(replicaNodeIds, replicaCount)
+}
+
+// Parses the count of replicas, which is either majority, all or a number. The majority is based on the replication
+// factor, independent of how many replicas are currently alive.
+func parseReplicaCount(replicas string, replicationFactor int) (int, error) {
+	switch replicas {
+	case "majority":
+		return replicationFactor/2 + 1, nil
+	case "all":
+		return replicationFactor, nil
+	}
 
-	return brokerPod.Name
+	count, err := strconv.Atoi(replicas)
+	if err != nil || count <= 0 || count > replicationFactor {
+		return 0, fmt.Errorf("expected replicas to be majority, all or a number between 1 and %d, but got '%s'", replicationFactor, replicas)
+	}
+	return count, nil
 }
 
 // Restarts all brokers in the current namespace.
-// GracePeriod (in second) can be nil, which would mean using K8 default.
-func restartBrokers(k8Client internal.K8Client, actionName string, gracePeriod *int64) {
+// GracePeriod (in second) can be nil, which would mean using K8 default. If evict is set, the pod is evicted instead.
+func restartBrokers(k8Client internal.K8Client, actionName string, gracePeriod *int64, evict bool) error {
 	brokerPodNames, err := k8Client.GetBrokerPodNames()
 	ensureNoError(err)
 
@@ -121,17 +291,22 @@ func restartBrokers(k8Client internal.K8Client, actionName string, gracePeriod *
 		panic(errors.New(fmt.Sprintf("Expected to find a Zeebe broker in namespace %s, but none found", k8Client.GetCurrentNamespace())))
 	}
 
+	var restartedPods []string
 	for _, brokerPodName := range brokerPodNames {
-		err = k8Client.RestartPodWithGracePeriod(brokerPodName, gracePeriod)
-		ensureNoError(err)
+		err = restartPod(k8Client, brokerPodName, gracePeriod, evict)
+		if err = checkRestarted(err, brokerPodName, restartedPods); err != nil {
+			return err
+		}
 		internal.LogInfo("%s %s", actionName, brokerPodName)
+		restartedPods = append(restartedPods, brokerPodName)
 	}
+	return nil
 }
 
 // Restart a gateway pod. The pod is the first from a list of existing pods.
-// GracePeriod (in second) can be nil, which would mean using K8 default.
-// Returns the gateway which has been restarted
-func restartGateway(k8Client internal.K8Client, gracePeriod *int64) string {
+// GracePeriod (in second) can be nil, which would mean using K8 default. If evict is set, the pod is evicted instead.
+// Returns the gateway which has been restarted, or an evictionBlockedError if the eviction is blocked
+func restartGateway(k8Client internal.K8Client, gracePeriod *int64, evict bool) (string, error) {
 	gatewayPodNames, err := k8Client.GetGatewayPodNames()
 	ensureNoError(err)
 
@@ -140,14 +315,16 @@ func restartGateway(k8Client internal.K8Client, gracePeriod *int64) string {
 	}
 
 	gatewayPod := gatewayPodNames[0]
-	err = k8Client.RestartPodWithGracePeriod(gatewayPod, gracePeriod)
-	ensureNoError(err)
-	return gatewayPod
+	err = restartPod(k8Client, gatewayPod, gracePeriod, evict)
+	if err = checkRestarted(err, gatewayPod, nil); err != nil {
+		return "", err
+	}
+	return gatewayPod, nil
 }
 
 // Restarts all gateways in the current namespace.
-// GracePeriod (in second) can be nil, which would mean using K8 default.
-func restartGateways(k8Client internal.K8Client, actionName string, gracePeriod *int64) {
+// GracePeriod (in second) can be nil, which would mean using K8 default. If evict is set, the pod is evicted instead.
+func restartGateways(k8Client internal.K8Client, actionName string, gracePeriod *int64, evict bool) error {
 	gatewayPodNames, err := k8Client.GetGatewayPodNames()
 	ensureNoError(err)
 
@@ -155,17 +332,22 @@ func restartGateways(k8Client internal.K8Client, actionName string, gracePeriod
 		panic(errors.New(fmt.Sprintf("Expected to find a Zeebe gateways in namespace %s, but none found", k8Client.GetCurrentNamespace())))
 	}
 
+	var restartedPods []string
 	for _, gatewayPodName := range gatewayPodNames {
-		err = k8Client.RestartPodWithGracePeriod(gatewayPodName, gracePeriod)
-		ensureNoError(err)
+		err = restartPod(k8Client, gatewayPodName, gracePeriod, evict)
+		if err = checkRestarted(err, gatewayPodName, restartedPods); err != nil {
+			return err
+		}
 		internal.LogInfo("%s %s", actionName, gatewayPodName)
+		restartedPods = append(restartedPods, gatewayPodName)
 	}
+	return nil
 }
 
 // Restart a worker pod. The pod is the first from a list of existing pods, if all is not specified.
-// GracePeriod (in second) can be nil, which would mean using K8 default.
+// GracePeriod (in second) can be nil, which would mean using K8 default. If evict is set, the pod is evicted instead.
 // The actionName specifies whether it was restarted or terminated to log the right thing.
-func restartWorker(k8Client internal.K8Client, all bool, actionName string, gracePeriod *int64) {
+func restartWorker(k8Client internal.K8Client, all bool, actionName string, gracePeriod *int64, evict bool) error {
 	workerPods, err := k8Client.GetWorkerPods()
 	ensureNoError(err)
 
@@ -174,16 +356,23 @@ func restartWorker(k8Client internal.K8Client, all bool, actionName string, grac
 	}
 
 	if all {
+		var restartedPods []string
 		for _, worker := range workerPods.Items {
-			err = k8Client.RestartPodWithGracePeriod(worker.Name, gracePeriod)
-			ensureNoError(err)
+			err = restartPod(k8Client, worker.Name, gracePeriod, evict)
+			if err = checkRestarted(err, worker.Name, restartedPods); err != nil {
+				return err
+			}
 			internal.LogInfo("%s %s", actionName, worker.Name)
+			restartedPods = append(restartedPods, worker.Name)
 		}
 	} else {
 		workerPod := workerPods.Items[0]
-		err = k8Client.RestartPodWithGracePeriod(workerPod.Name, gracePeriod)
-		ensureNoError(err)
+		err = restartPod(k8Client, workerPod.Name, gracePeriod, evict)
+		if err = checkRestarted(err, workerPod.Name, nil); err != nil {
+			return err
+		}
 
 		internal.LogInfo("%s %s", actionName, workerPod.Name)
 	}
+	return nil
 }
@@ -0,0 +1,163 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseReplicaCount(t *testing.T) {
+	// given
+	replicationFactor := 5
+
+	// when
+	majority, majorityErr := parseReplicaCount("majority", replicationFactor)
+	all, allErr := parseReplicaCount("all", replicationFactor)
+	two, twoErr := parseReplicaCount("2", replicationFactor)
+	_, tooManyErr := parseReplicaCount("6", replicationFactor)
+
+	// then
+	require.NoError(t, majorityErr)
+	require.NoError(t, allErr)
+	require.NoError(t, twoErr)
+	require.Error(t, tooManyErr)
+	assert.Equal(t, 3, majority)
+	assert.Equal(t, 5, all)
+	assert.Equal(t, 2, two)
+}
+
+func Test_ChooseMajorityOfPartitionReplicasToTerminate(t *testing.T) {
+	// given
+	topology := &pb.TopologyResponse{
+		ClusterSize:       4,
+		ReplicationFactor: 3,
+		Brokers: []*pb.BrokerInfo{
+			{NodeId: 0, Partitions: []*pb.Partition{{PartitionId: 1}}},
+			{NodeId: 1, Partitions: []*pb.Partition{{PartitionId: 2}}},
+			{NodeId: 2, Partitions: []*pb.Partition{{PartitionId: 1}, {PartitionId: 2}}},
+			{NodeId: 3, Partitions: []*pb.Partition{{PartitionId: 1}}},
+		},
+	}
+
+	// when
+	nodeIds, err := chooseBrokersToTerminate(topology, 0, 1, "majority")
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, nodeIds, 2)
+	assert.Subset(t, []int{0, 2, 3}, nodeIds)
+}
+
+func Test_ChooseMajorityBasedOnReplicationFactor(t *testing.T) {
+	// given
+	topology := &pb.TopologyResponse{
+		ClusterSize:       5,
+		ReplicationFactor: 5,
+		Brokers: []*pb.BrokerInfo{
+			{NodeId: 0, Partitions: []*pb.Partition{{PartitionId: 1}}},
+			{NodeId: 1, Partitions: []*pb.Partition{{PartitionId: 1}}},
+			{NodeId: 2, Partitions: []*pb.Partition{{PartitionId: 1}}},
+			{NodeId: 3, Partitions: []*pb.Partition{{PartitionId: 1}}},
+		},
+	}
+
+	// when
+	nodeIds, err := chooseBrokersToTerminate(topology, 0, 1, "majority")
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, nodeIds, 3)
+}
+
+func Test_FailIfNotEnoughReplicasAreAliveToTerminate(t *testing.T) {
+	// given
+	topology := &pb.TopologyResponse{
+		ClusterSize:       3,
+		ReplicationFactor: 3,
+		Brokers: []*pb.BrokerInfo{
+			{NodeId: 0, Partitions: []*pb.Partition{{PartitionId: 1}}},
+		},
+	}
+
+	// when
+	_, err := chooseBrokersToTerminate(topology, 0, 1, "majority")
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected to terminate 2 replicas of partition 1, but only 1 replicas are part of the topology")
+}
+
+func Test_ChooseCountOfBrokersToTerminate(t *testing.T) {
+	// given
+	topology := &pb.TopologyResponse{ClusterSize: 3}
+
+	// when
+	nodeIds, err := chooseBrokersToTerminate(topology, 3, 1, "majority")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, nodeIds)
+}
+
+func Test_ShouldDescribeBlockedEvictionWithoutRestartedPods(t *testing.T) {
+	// given
+
+	// when
+	message := describeBlockedEviction("zeebe-0", nil)
+
+	// then
+	assert.Equal(t, "The PodDisruptionBudget of pod zeebe-0 doesn't allow to evict it, no pod has been restarted.", message)
+}
+
+func Test_ShouldDescribeBlockedEvictionWithAlreadyRestartedPods(t *testing.T) {
+	// given
+	restartedPods := []string{"zeebe-0", "zeebe-1"}
+
+	// when
+	message := describeBlockedEviction("zeebe-2", restartedPods)
+
+	// then
+	assert.Equal(t, "The PodDisruptionBudget of pod zeebe-2 doesn't allow to evict it, the pods [zeebe-0 zeebe-1] have already been restarted.", message)
+}
+
+func Test_ShouldReturnEvictionBlockedError(t *testing.T) {
+	// given
+	err := fmt.Errorf("failed to evict pod zeebe-1, %w: too many requests", internal.ErrEvictionBlocked)
+
+	// when
+	restartErr := checkRestarted(err, "zeebe-1", []string{"zeebe-0"})
+
+	// then
+	require.Error(t, restartErr)
+	assert.ErrorAs(t, restartErr, &evictionBlockedError{})
+	assert.ErrorIs(t, restartErr, internal.ErrEvictionBlocked)
+	assert.Equal(t, "The PodDisruptionBudget of pod zeebe-1 doesn't allow to evict it, the pods [zeebe-0] have already been restarted.", restartErr.Error())
+}
+
+func Test_ShouldNotReturnErrorIfRestarted(t *testing.T) {
+	// given
+
+	// when
+	err := checkRestarted(nil, "zeebe-1", nil)
+
+	// then
+	assert.NoError(t, err)
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/camunda/zeebe-chaos/go-chaos/backend"
+	"github.com/spf13/cobra"
+)
+
+func AddThrottleCommand(rootCmd *cobra.Command, flags *Flags) {
+	throttle := &cobra.Command{
+		Use:   "throttle",
+		Short: "Throttle the network bandwidth of Zeebe nodes",
+		Long: `Throttle the network bandwidth of Zeebe nodes, uses sub-commands to select the node.
+The bandwidth is limited via tc/tbf and can be restored again with the connect command, or automatically via --duration.`,
+	}
+
+	throttleBroker := &cobra.Command{
+		Use:   "broker",
+		Short: "Throttle the egress bandwidth of a Zeebe Broker",
+		Long: `Throttle the egress bandwidth of a Zeebe Broker. Broker can be identified via ID or partition and role.
+Per default all egress traffic of the Broker is throttled, peers can be specified to only throttle the traffic towards them.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			var peers []backend.Broker
+			for _, peerNodeId := range flags.peerNodeIds {
+				peers = append(peers, backend.Broker{NodeId: peerNodeId})
+			}
+			if flags.peerPartitionId > 0 {
+				peers = append(peers, backend.Broker{NodeId: -1, PartitionId: flags.peerPartitionId, Role: flags.peerRole})
+			}
+
+			err := backend.ThrottleBroker(flags.kubeConfigPath, flags.namespace, backend.ThrottleBrokerCfg{
+				BrokerCfg: backend.Broker{
+					NodeId:      flags.nodeId,
+					PartitionId: flags.partitionId,
+					Role:        flags.role,
+				},
+				Peers:    peers,
+				Rate:     flags.bandwidthRate,
+				Burst:    flags.bandwidthBurst,
+				Duration: flags.duration,
+			}, makeClientCredentials(flags))
+			ensureNoError(err)
+		},
+	}
+
+	rootCmd.AddCommand(throttle)
+	throttle.AddCommand(throttleBroker)
+	throttleBroker.Flags().IntVar(&flags.nodeId, "nodeId", -1, "Specify the nodeId of the Broker")
+	throttleBroker.Flags().StringVar(&flags.role, "role", "LEADER", "Specify the partition role [LEADER, FOLLOWER] of the Broker")
+	throttleBroker.Flags().IntVar(&flags.partitionId, "partitionId", 1, "Specify the partition id of the Broker")
+	throttleBroker.MarkFlagsMutuallyExclusive("partitionId", "nodeId")
+	throttleBroker.Flags().StringVar(&flags.bandwidthRate, "rate", "1mbit", "Specify the bandwidth limit, e.g. 1mbit or 500kbit")
+	throttleBroker.Flags().StringVar(&flags.bandwidthBurst, "burst", "32kbit", "Specify the size of the token bucket, higher rates require bigger buckets")
+	addDurationFlag(throttleBroker, flags)
+	// peers
+	throttleBroker.Flags().IntSliceVar(&flags.peerNodeIds, "peerNodeId", []int{}, "Specify the nodeId(s) of the peer Broker(s) towards which the traffic should be throttled, can be repeated")
+	throttleBroker.Flags().IntVar(&flags.peerPartitionId, "peerPartitionId", 0, "Specify the partition id of the peer Broker towards which the traffic should be throttled")
+	throttleBroker.Flags().StringVar(&flags.peerRole, "peerRole", "FOLLOWER", "Specify the partition role [LEADER, FOLLOWER] of the peer Broker")
+}
@@ -16,7 +16,10 @@ package cmd
 
 import (
 	"context"
+	"math/rand"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/camunda/zeebe-chaos/go-chaos/internal"
 	worker "github.com/camunda/zeebe-chaos/go-chaos/worker"
@@ -30,20 +33,119 @@ import (
 const jobTypeZbChaos = "zbchaos"
 const jobTypeReadExperiments = "readExperiments"
 
+// How many jobs the benchmark worker handles at the same time
+const benchmarkWorkerCapacity = 10
+
 const ENV_AUTHORIZATION_SERVER_URL = "CHAOS_AUTOMATION_CLUSTER_AUTHORIZATION_SERVER_URL"
 const ENV_CLIENT_ID = "CHAOS_AUTOMATION_CLUSTER_CLIENT_ID"
 const ENV_CLIENT_SECRET = "CHAOS_AUTOMATION_CLUSTER_CLIENT_SECRET"
 const ENV_ADDRESS = "CHAOS_AUTOMATION_CLUSTER_ADDRESS"
 const ENV_AUDIENCE = "CHAOS_AUTOMATION_CLUSTER_AUDIENCE"
 
-func AddWorkerCmd(rootCmd *cobra.Command) {
+func AddWorkerCmd(rootCmd *cobra.Command, flags *Flags) {
 	var workerCommand = &cobra.Command{
 		Use:   "worker",
 		Short: "Starts a worker for zbchaos jobs",
 		Long:  "Starts a worker for zbchaos jobs that executes zbchaos commands",
 		Run:   start_worker,
 	}
+
+	var scaleCommand = &cobra.Command{
+		Use:   "scale",
+		Short: "Scales the deployed benchmark workers",
+		Long: `Scales the benchmark worker deployment, created via 'deploy worker'.
+With --toZero all workers are stopped, the previous replicas are stored as annotation on the deployment.
+If a duration is given the previous replicas are restored afterwards, which lets all workers reconnect at once.
+If zbchaos is interrupted before, the previous replicas can be restored via --restore.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			k8Client, err := createK8ClientWithFlags(flags)
+			ensureNoError(err)
+
+			if flags.workerRestore {
+				restoreWorkers(k8Client)
+				return
+			}
+
+			if !flags.workerToZero {
+				previousReplicas, err := k8Client.ScaleWorkerDeployment(flags.workerReplicas)
+				ensureNoError(err)
+				internal.LogInfo("Scaled workers from %d to %d replicas", previousReplicas, flags.workerReplicas)
+				return
+			}
+
+			previousReplicas, err := k8Client.StopWorkerDeployment()
+			ensureNoError(err)
+			internal.LogInfo("Scaled workers from %d to 0 replicas", previousReplicas)
+			if flags.duration <= 0 {
+				return
+			}
+
+			internal.LogInfo("Will restore the workers after %s", flags.duration)
+			time.Sleep(flags.duration)
+			restoreWorkers(k8Client)
+		},
+	}
+
+	var benchmarkCommand = &cobra.Command{
+		Use:   "benchmark",
+		Short: "Starts a benchmark worker",
+		Long: `Starts a worker, which handles the jobs of the benchmark processes. It is deployed via 'deploy worker', if jobs should be failed, rejected or time out.
+The given percentages of the jobs are failed (with decreased retries), rejected with the BPMN error '` + worker.BenchmarkErrorCode + `' or never completed, such that they time out.
+All other jobs are completed after the completion delay.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client, err := internal.CreateZeebeClientForAddress(flags.gatewayAddress, makeClientCredentials(flags))
+			ensureNoError(err)
+			defer client.Close()
+
+			cfg := worker.BenchmarkWorkerConfig{
+				CompletionDelay:     flags.completionDelay,
+				FailurePercentage:   flags.failurePercentage,
+				BpmnErrorPercentage: flags.bpmnErrorPercentage,
+				TimeoutPercentage:   flags.timeoutPercentage,
+			}
+			random := rand.New(rand.NewSource(time.Now().UnixNano()))
+			var randomLock sync.Mutex
+			handler := func(jobClient zbworker.JobClient, job entities.Job) {
+				randomLock.Lock()
+				roll := random.Float64() * 100
+				randomLock.Unlock()
+				worker.HandleBenchmarkJob(jobClient, job, cfg, roll)
+			}
+
+			internal.LogInfo("Open benchmark worker for jobs of type %s on %s", flags.workerJobType, flags.gatewayAddress)
+			jobWorker := client.NewJobWorker().JobType(flags.workerJobType).Handler(handler).
+				MaxJobsActive(benchmarkWorkerCapacity).PollInterval(time.Duration(flags.pollingDelayMs) * time.Millisecond).Open()
+			jobWorker.AwaitClose()
+		},
+	}
+
 	rootCmd.AddCommand(workerCommand)
+	workerCommand.AddCommand(benchmarkCommand)
+	defaultWorkerOptions := internal.DefaultWorkerOptions(DockerImageTag, 1)
+	benchmarkCommand.Flags().StringVar(&flags.gatewayAddress, "gatewayAddress", "", "Specify the address of the gateway, e.g. the gateway service inside the cluster")
+	benchmarkCommand.Flags().IntVar(&flags.pollingDelayMs, "pollingDelay", 1, "Specifies the worker's polling interval in milliseconds")
+	benchmarkCommand.Flags().StringVar(&flags.workerJobType, "jobType", defaultWorkerOptions.JobType, "Specifies the type of the jobs, which are handled by the worker")
+	benchmarkCommand.Flags().DurationVar(&flags.completionDelay, "completionDelay", defaultWorkerOptions.CompletionDelay, "Specifies how long the worker takes to handle a job")
+	benchmarkCommand.Flags().Float64Var(&flags.failurePercentage, "failurePercentage", 0, "Specifies the percentage of jobs, which are failed by the worker")
+	benchmarkCommand.Flags().Float64Var(&flags.bpmnErrorPercentage, "bpmnErrorPercentage", 0, "Specifies the percentage of jobs, for which the worker throws a BPMN error")
+	benchmarkCommand.Flags().Float64Var(&flags.timeoutPercentage, "timeoutPercentage", 0, "Specifies the percentage of jobs, which are never completed by the worker, such that they time out")
+	benchmarkCommand.MarkFlagRequired("gatewayAddress")
+
+	workerCommand.AddCommand(scaleCommand)
+	scaleCommand.Flags().Int32Var(&flags.workerReplicas, "replicas", 1, "Specify the count of worker replicas")
+	scaleCommand.Flags().BoolVar(&flags.workerToZero, "toZero", false, "Specify whether all workers should be stopped")
+	scaleCommand.Flags().DurationVar(&flags.duration, "duration", 0, "Specify how long the workers should be stopped (e.g. 5m), per default they are not restored. If interrupted, restore them via --restore")
+	scaleCommand.Flags().BoolVar(&flags.workerRestore, "restore", false, "Specify whether the workers, stopped via --toZero, should be scaled back to their previous replicas")
+	scaleCommand.MarkFlagsOneRequired("replicas", "toZero", "restore")
+	scaleCommand.MarkFlagsMutuallyExclusive("replicas", "toZero", "restore")
+	scaleCommand.MarkFlagsMutuallyExclusive("replicas", "duration")
+	scaleCommand.MarkFlagsMutuallyExclusive("restore", "duration")
+}
+
+func restoreWorkers(k8Client internal.K8Client) {
+	replicas, err := k8Client.RestoreWorkerDeployment()
+	ensureNoError(err)
+	internal.LogInfo("Scaled workers back to %d replicas", replicas)
 }
 
 func start_worker(cmd *cobra.Command, args []string) {
@@ -23,13 +23,21 @@ import (
 	"strconv"
 	"strings"
 	template "text/template"
+	"time"
 
 	v12 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/util/retry"
 )
 
+// The name of the deployment created by CreateWorkerDeployment, see manifests/worker.yaml
+const workerDeploymentName = "worker"
+
+// Stores the replicas of the worker deployment before it has been stopped, see StopWorkerDeployment
+const workerPreviousReplicasAnnotation = "zbchaos.camunda.io/previous-replicas"
+
 // k8Deployments holds our static k8 manifests, which are copied with the go:embed directive
 //
 //go:embed manifests/*
@@ -56,30 +64,117 @@ func (c K8Client) CreateWorkerDeploymentDefault() error {
 	return c.CreateWorkerDeployment("zeebe", 1, &ClientCredentials{})
 }
 
+// WorkerOptions configure the behavior of the deployed benchmark worker, see manifests/worker.yaml
+type WorkerOptions struct {
+	DockerImageTag  string
+	PollingDelayMs  int
+	Replicas        int32
+	JobType         string
+	CompletionDelay time.Duration
+	// The percentages of jobs which are failed, rejected with a BPMN error or never completed, such that they time out.
+	// They are only supported by the zbchaos benchmark worker, which is deployed if any percentage is set.
+	FailurePercentage   float64
+	BpmnErrorPercentage float64
+	TimeoutPercentage   float64
+	// The tag of the zbchaos image, which is used for the benchmark worker, see manifests/benchmark-worker.yaml
+	ChaosImageTag string
+	// The resources of the worker container, as Kubernetes quantities
+	CpuRequest    string
+	MemoryRequest string
+	CpuLimit      string
+	MemoryLimit   string
+}
+
+// Returns the options of the worker deployment, which are used if no other options are given
+func DefaultWorkerOptions(dockerImageTag string, pollingDelayMs int) WorkerOptions {
+	return WorkerOptions{
+		DockerImageTag:  dockerImageTag,
+		PollingDelayMs:  pollingDelayMs,
+		Replicas:        3,
+		JobType:         "benchmark-task",
+		CompletionDelay: 50 * time.Millisecond,
+		CpuRequest:      "1",
+		MemoryRequest:   "512Mi",
+		CpuLimit:        "4",
+		MemoryLimit:     "2Gi",
+	}
+}
+
+// Returns whether the zbchaos benchmark worker is needed, since the jobs shouldn't only be completed
+func (options WorkerOptions) usesBenchmarkWorker() bool {
+	return options.FailurePercentage > 0 || options.BpmnErrorPercentage > 0 || options.TimeoutPercentage > 0
+}
+
+func (options WorkerOptions) validate() error {
+	for name, percentage := range map[string]float64{"failure": options.FailurePercentage, "BPMN error": options.BpmnErrorPercentage, "timeout": options.TimeoutPercentage} {
+		if percentage < 0 || percentage > 100 {
+			return fmt.Errorf("expected the %s percentage to be between 0 and 100, but got %v", name, percentage)
+		}
+	}
+	if options.FailurePercentage+options.BpmnErrorPercentage+options.TimeoutPercentage > 100 {
+		return fmt.Errorf("expected the sum of the failure, BPMN error and timeout percentages to be at most 100, but got %v",
+			options.FailurePercentage+options.BpmnErrorPercentage+options.TimeoutPercentage)
+	}
+	if options.usesBenchmarkWorker() && (options.ChaosImageTag == "" || options.ChaosImageTag == "development") {
+		return fmt.Errorf("expected a released zbchaos image tag for the benchmark worker, which fails jobs, but got '%s'", options.ChaosImageTag)
+	}
+	if options.Replicas < 0 {
+		return fmt.Errorf("expected the worker replicas to be positive, but got %d", options.Replicas)
+	}
+	return nil
+}
+
 func (c K8Client) CreateWorkerDeployment(dockerImageTag string, pollingDelayMs int, credentials *ClientCredentials) error {
+	return c.CreateWorkerDeploymentWithOptions(DefaultWorkerOptions(dockerImageTag, pollingDelayMs), credentials)
+}
+
+func (c K8Client) CreateWorkerDeploymentWithOptions(options WorkerOptions, credentials *ClientCredentials) error {
+	err := options.validate()
+	if err != nil {
+		return err
+	}
+
 	serviceName, err := c.resolveGatewayServiceName()
 	if err != nil {
 		return err
 	}
 
-	templateFile, err := k8Deployments.ReadFile("manifests/worker.yaml")
+	templateName := "worker.yaml"
+	if options.usesBenchmarkWorker() {
+		templateName = "benchmark-worker.yaml"
+	}
+	templateFile, err := k8Deployments.ReadFile("manifests/" + templateName)
 	if err != nil {
 		return err
 	}
 	// the template name must be the filename
-	tmpl, err := template.New("worker.yaml").Parse(string(templateFile))
+	tmpl, err := template.New(templateName).Parse(string(templateFile))
 	if err != nil {
 		return err
 	}
-	pollingDelayStr := strconv.FormatInt(int64(pollingDelayMs), 10) + "ms"
+	pollingDelayStr := strconv.FormatInt(int64(options.PollingDelayMs), 10) + "ms"
+	// the worker expects durations in the simple format, e.g. 50ms
+	completionDelayStr := strconv.FormatInt(options.CompletionDelay.Milliseconds(), 10) + "ms"
 	workerBuilder := new(strings.Builder)
 	replacements := TemplateReplacements{
-		ImageTag:          dockerImageTag,
-		PollingDelay:      pollingDelayStr,
-		ClientCredentials: *credentials,
-		ServiceName:       serviceName,
+		ImageTag:            options.DockerImageTag,
+		ChaosImageTag:       options.ChaosImageTag,
+		PollingDelay:        pollingDelayStr,
+		PollingDelayMs:      options.PollingDelayMs,
+		ClientCredentials:   *credentials,
+		ServiceName:         serviceName,
+		Replicas:            options.Replicas,
+		JobType:             options.JobType,
+		CompletionDelay:     completionDelayStr,
+		FailurePercentage:   options.FailurePercentage,
+		BpmnErrorPercentage: options.BpmnErrorPercentage,
+		TimeoutPercentage:   options.TimeoutPercentage,
+		CpuRequest:          options.CpuRequest,
+		MemoryRequest:       options.MemoryRequest,
+		CpuLimit:            options.CpuLimit,
+		MemoryLimit:         options.MemoryLimit,
 	}
-	err = tmpl.ExecuteTemplate(workerBuilder, "worker.yaml", replacements)
+	err = tmpl.ExecuteTemplate(workerBuilder, templateName, replacements)
 	if err != nil {
 		return err
 	}
@@ -128,6 +223,76 @@ func (c K8Client) CreateWorkerDeployment(dockerImageTag string, pollingDelayMs i
 	return err
 }
 
+// Scales the worker deployment to the given count of replicas. Returns the count of replicas before scaling.
+func (c K8Client) ScaleWorkerDeployment(replicas int32) (int32, error) {
+	return c.updateWorkerReplicas(func(deployment *v12.Deployment, currentReplicas int32) (int32, error) {
+		return replicas, nil
+	})
+}
+
+// Scales the worker deployment to zero replicas. The replicas before scaling are stored as annotation on the
+// deployment, such that they can be restored via RestoreWorkerDeployment, e.g. by a later zbchaos run.
+// Returns the count of replicas before scaling.
+func (c K8Client) StopWorkerDeployment() (int32, error) {
+	return c.updateWorkerReplicas(func(deployment *v12.Deployment, currentReplicas int32) (int32, error) {
+		if _, stopped := deployment.Annotations[workerPreviousReplicasAnnotation]; stopped && currentReplicas == 0 {
+			// the workers have been stopped before, keep the replicas which should be restored
+			return 0, nil
+		}
+		if deployment.Annotations == nil {
+			deployment.Annotations = map[string]string{}
+		}
+		deployment.Annotations[workerPreviousReplicasAnnotation] = strconv.Itoa(int(currentReplicas))
+		return 0, nil
+	})
+}
+
+// Scales the worker deployment back to the replicas, which have been stored by StopWorkerDeployment.
+// Returns the count of restored replicas.
+func (c K8Client) RestoreWorkerDeployment() (int32, error) {
+	var restoredReplicas int32
+	_, err := c.updateWorkerReplicas(func(deployment *v12.Deployment, currentReplicas int32) (int32, error) {
+		value, stopped := deployment.Annotations[workerPreviousReplicasAnnotation]
+		if !stopped {
+			return 0, fmt.Errorf("expected worker deployment to be annotated with %s by 'worker scale --toZero', but no annotation found", workerPreviousReplicasAnnotation)
+		}
+		replicas, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse the previous replicas '%s' of the worker deployment: %w", value, err)
+		}
+
+		delete(deployment.Annotations, workerPreviousReplicasAnnotation)
+		restoredReplicas = int32(replicas)
+		return restoredReplicas, nil
+	})
+	return restoredReplicas, err
+}
+
+// Updates the replicas of the worker deployment to the replicas returned by the given function, which can modify the
+// deployment as well. Returns the count of replicas before the update.
+func (c K8Client) updateWorkerReplicas(update func(deployment *v12.Deployment, currentReplicas int32) (int32, error)) (int32, error) {
+	var previousReplicas int32
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		deployment, err := c.Clientset.AppsV1().Deployments(c.GetCurrentNamespace()).Get(context.TODO(), workerDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		previousReplicas = 1
+		if deployment.Spec.Replicas != nil {
+			previousReplicas = *deployment.Spec.Replicas
+		}
+		replicas, err := update(deployment, previousReplicas)
+		if err != nil {
+			return err
+		}
+		deployment.Spec.Replicas = &replicas
+		_, err = c.Clientset.AppsV1().Deployments(c.GetCurrentNamespace()).Update(context.TODO(), deployment, metav1.UpdateOptions{})
+		return err
+	})
+	return previousReplicas, err
+}
+
 func (c K8Client) resolveGatewayServiceName() (_ string, err error) {
 	defer func() {
 		if recovered := recover(); recovered != nil {
@@ -140,10 +305,22 @@ func (c K8Client) resolveGatewayServiceName() (_ string, err error) {
 }
 
 type TemplateReplacements struct {
-	PollingDelay string
-	ImageTag     string
+	PollingDelay   string
+	PollingDelayMs int
+	ImageTag       string
+	ChaosImageTag  string
 	ClientCredentials
-	ServiceName string
+	ServiceName         string
+	Replicas            int32
+	JobType             string
+	CompletionDelay     string
+	FailurePercentage   float64
+	BpmnErrorPercentage float64
+	TimeoutPercentage   float64
+	CpuRequest          string
+	MemoryRequest       string
+	CpuLimit            string
+	MemoryLimit         string
 }
 
 // Replaces a given string for a substitution in the JAVA_OPTIONS env var
@@ -17,6 +17,7 @@ package internal
 import (
 	"context"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 
@@ -390,3 +391,239 @@ func Test_ShouldRenderAuthMethodNoneWhenNoCredentials(t *testing.T) {
 	assert.Equal(t, "none", envValue(envs, "CAMUNDA_CLIENT_AUTH_METHOD"))
 	assert.Equal(t, "", envValue(envs, "CAMUNDA_CLIENT_AUTH_CLIENT_ID"))
 }
+
+func Test_ShouldScaleWorkerDeployment(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	err := k8Client.CreateWorkerDeploymentDefault()
+	require.NoError(t, err)
+
+	// when
+	previousReplicas, err := k8Client.ScaleWorkerDeployment(0)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), previousReplicas)
+	deployment, err := k8Client.Clientset.AppsV1().Deployments(k8Client.GetCurrentNamespace()).Get(context.TODO(), "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+}
+
+func Test_ShouldStoreReplicasOnStopWorkerDeployment(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	err := k8Client.CreateWorkerDeploymentDefault()
+	require.NoError(t, err)
+
+	// when
+	previousReplicas, err := k8Client.StopWorkerDeployment()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), previousReplicas)
+	deployment, err := k8Client.Clientset.AppsV1().Deployments(k8Client.GetCurrentNamespace()).Get(context.TODO(), "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+	assert.Equal(t, "3", deployment.Annotations["zbchaos.camunda.io/previous-replicas"])
+}
+
+func Test_ShouldKeepStoredReplicasOnRepeatedStopWorkerDeployment(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	err := k8Client.CreateWorkerDeploymentDefault()
+	require.NoError(t, err)
+	_, err = k8Client.StopWorkerDeployment()
+	require.NoError(t, err)
+
+	// when
+	_, err = k8Client.StopWorkerDeployment()
+	require.NoError(t, err)
+	restoredReplicas, err := k8Client.RestoreWorkerDeployment()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), restoredReplicas)
+}
+
+func Test_ShouldRestoreStoppedWorkerDeployment(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	err := k8Client.CreateWorkerDeploymentDefault()
+	require.NoError(t, err)
+	_, err = k8Client.StopWorkerDeployment()
+	require.NoError(t, err)
+
+	// when
+	restoredReplicas, err := k8Client.RestoreWorkerDeployment()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), restoredReplicas)
+	deployment, err := k8Client.Clientset.AppsV1().Deployments(k8Client.GetCurrentNamespace()).Get(context.TODO(), "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	assert.NotContains(t, deployment.Annotations, "zbchaos.camunda.io/previous-replicas")
+}
+
+func Test_ShouldFailToRestoreNotStoppedWorkerDeployment(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	err := k8Client.CreateWorkerDeploymentDefault()
+	require.NoError(t, err)
+
+	// when
+	_, err = k8Client.RestoreWorkerDeployment()
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no annotation found")
+	deployment, err := k8Client.Clientset.AppsV1().Deployments(k8Client.GetCurrentNamespace()).Get(context.TODO(), "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+}
+
+func Test_ShouldFailToScaleNonExistingWorkerDeployment(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+
+	// when
+	_, err := k8Client.ScaleWorkerDeployment(5)
+
+	// then
+	require.Error(t, err)
+}
+
+func Test_ShouldDeployWorkerDeploymentWithOptions(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	options := DefaultWorkerOptions("testTag", 1)
+	options.Replicas = 5
+	options.JobType = "chaos-task"
+	options.CompletionDelay = 2 * time.Second
+	options.CpuLimit = "500m"
+	options.MemoryLimit = "1Gi"
+
+	// when
+	err := k8Client.CreateWorkerDeploymentWithOptions(options, mockedCredentials())
+
+	// then
+	require.NoError(t, err)
+	deployment, err := k8Client.Clientset.AppsV1().Deployments(k8Client.GetCurrentNamespace()).Get(context.TODO(), "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(5), *deployment.Spec.Replicas)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "gcr.io/zeebe-io/worker:testTag", container.Image)
+	env := map[string]string{}
+	for _, envVar := range container.Env {
+		env[envVar.Name] = envVar.Value
+	}
+	assert.Equal(t, "chaos-task", env["LOAD_TESTER_WORKER_JOB_TYPE"])
+	assert.Equal(t, "2000ms", env["LOAD_TESTER_WORKER_COMPLETION_DELAY"])
+	assert.Contains(t, env["JDK_JAVA_OPTIONS"], "-Dapp.worker.jobType=chaos-task")
+	assert.Equal(t, "500m", container.Resources.Limits.Cpu().String())
+	assert.Equal(t, "1Gi", container.Resources.Limits.Memory().String())
+	assert.Equal(t, "1", container.Resources.Requests.Cpu().String())
+}
+
+func Test_ShouldDeployBenchmarkWorkerIfJobsShouldFail(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	options := DefaultWorkerOptions("testTag", 1)
+	options.ChaosImageTag = "1.0.0"
+	options.Replicas = 5
+	options.JobType = "chaos-task"
+	options.CompletionDelay = 2 * time.Second
+	options.FailurePercentage = 10
+	options.BpmnErrorPercentage = 5
+	options.TimeoutPercentage = 2.5
+	options.CpuLimit = "500m"
+
+	// when
+	err := k8Client.CreateWorkerDeploymentWithOptions(options, mockedCredentials())
+
+	// then
+	require.NoError(t, err)
+	deployment, err := k8Client.Clientset.AppsV1().Deployments(k8Client.GetCurrentNamespace()).Get(context.TODO(), "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(5), *deployment.Spec.Replicas)
+	assert.Equal(t, "worker", deployment.Spec.Template.Labels["app"])
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "gcr.io/zeebe-io/zbchaos:1.0.0", container.Image)
+	assert.Equal(t, []string{
+		"worker", "benchmark", "--jsonLogging",
+		"--gatewayAddress=sm-gateway-service:26500",
+		"--jobType=chaos-task",
+		"--pollingDelay=1",
+		"--completionDelay=2000ms",
+		"--failurePercentage=10",
+		"--bpmnErrorPercentage=5",
+		"--timeoutPercentage=2.5",
+		"--authServer=AuthServer",
+		"--audience=Audience",
+		"--clientId=ClientId",
+		"--clientSecret=SuperSecret",
+	}, container.Args)
+	assert.Equal(t, "500m", container.Resources.Limits.Cpu().String())
+}
+
+func Test_ShouldDeployBenchmarkWorkerWithoutCredentials(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	options := DefaultWorkerOptions("testTag", 1)
+	options.ChaosImageTag = "1.0.0"
+	options.TimeoutPercentage = 50
+
+	// when
+	err := k8Client.CreateWorkerDeploymentWithOptions(options, &ClientCredentials{})
+
+	// then
+	require.NoError(t, err)
+	deployment, err := k8Client.Clientset.AppsV1().Deployments(k8Client.GetCurrentNamespace()).Get(context.TODO(), "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+	args := deployment.Spec.Template.Spec.Containers[0].Args
+	assert.Contains(t, args, "--timeoutPercentage=50")
+	// no auth flags are passed
+	assert.Len(t, args, 10)
+}
+
+func Test_ShouldRejectBenchmarkWorkerOfDevelopmentVersion(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	options := DefaultWorkerOptions("testTag", 1)
+	options.ChaosImageTag = "development"
+	options.FailurePercentage = 10
+
+	// when
+	err := k8Client.CreateWorkerDeploymentWithOptions(options, mockedCredentials())
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected a released zbchaos image tag")
+}
+
+func Test_ShouldRejectInvalidWorkerPercentages(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	setupGatewayServiceTarget(t, k8Client, "sm-gateway-service")
+	options := DefaultWorkerOptions("testTag", 1)
+	options.FailurePercentage = 60
+	options.TimeoutPercentage = 50
+
+	// when
+	err := k8Client.CreateWorkerDeploymentWithOptions(options, mockedCredentials())
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at most 100")
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The data directory of the broker, which is backed by the persistent volume
+const zeebeDataDirectory = "/usr/local/zeebe/data"
+
+// The file which is written to fill the disk, it is removed again on release
+const ballastFile = zeebeDataDirectory + "/zbchaos-ballast"
+
+// DiskFillCfg describes up to which threshold the disk is filled, either the usage percentage or the free bytes is set.
+type DiskFillCfg struct {
+	// the percentage of the volume which should be used after filling, e.g. 95
+	UsagePercentage float64
+	// the bytes which should be still free after filling, e.g. 1073741824 for 1GiB
+	FreeBytes int64
+}
+
+// Fills the data volume of the given broker pod with a ballast file, until the given threshold is reached.
+// An existing ballast file is replaced, such that the disk can be filled again with a different threshold.
+// Returns the size of the ballast file in bytes, which is zero if the threshold is already reached.
+func FillDiskOfPod(k8Client K8Client, podName string, diskFillCfg DiskFillCfg) (int64, error) {
+	if diskFillCfg.UsagePercentage < 0 || diskFillCfg.UsagePercentage > 100 {
+		return 0, fmt.Errorf("expected usage percentage to be between 0 and 100, but got %v", diskFillCfg.UsagePercentage)
+	}
+	// otherwise calculateBallastSize falls back to the free bytes, and a usage percentage of 0 would fill the whole volume
+	if diskFillCfg.UsagePercentage == 0 && diskFillCfg.FreeBytes <= 0 {
+		return 0, errors.New("expected either a usage percentage or free bytes greater than 0, use a usage percentage of 100 to fill the whole volume")
+	}
+
+	output := bytes.Buffer{}
+	cmd := fmt.Sprintf("rm -f %s && df -B1 --output=size,avail %s", ballastFile, zeebeDataDirectory)
+	err := k8Client.ExecuteCmdOnPodWriteIntoOutput([]string{"sh", "-c", cmd}, podName, &output)
+	if err != nil {
+		return 0, err
+	}
+
+	size, available, err := parseDfOutput(output.String())
+	if err != nil {
+		return 0, err
+	}
+	LogVerbose("Data volume of %s has %d of %d bytes available", podName, available, size)
+
+	ballastSize := calculateBallastSize(size, available, diskFillCfg)
+	if ballastSize <= 0 {
+		return 0, nil
+	}
+
+	// fallocate is not supported by all file systems, in this case we write the file
+	cmd = fmt.Sprintf("fallocate -l %[1]d %[2]s || head -c %[1]d /dev/zero > %[2]s", ballastSize, ballastFile)
+	err = k8Client.ExecuteCmdOnPod([]string{"sh", "-c", cmd}, podName)
+	if err != nil {
+		return 0, err
+	}
+	return ballastSize, nil
+}
+
+// Removes the ballast file from the data volume of the given broker pod.
+func ReleaseDiskOfPod(k8Client K8Client, podName string) error {
+	return k8Client.ExecuteCmdOnPod([]string{"rm", "-f", ballastFile}, podName)
+}
+
+// Parses the output of 'df -B1 --output=size,avail', which consists of a header and a line with the size and
+// available bytes.
+func parseDfOutput(output string) (int64, int64, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return 0, 0, fmt.Errorf("expected df output with header and values, but got '%s'", output)
+	}
+
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected df output with size and available bytes, but got '%s'", output)
+	}
+
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	available, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return size, available, nil
+}
+
+// Calculates the size of the ballast file, such that the given threshold is reached. Might be negative,
+// if the threshold is already reached.
+func calculateBallastSize(size int64, available int64, diskFillCfg DiskFillCfg) int64 {
+	targetAvailable := diskFillCfg.FreeBytes
+	if diskFillCfg.UsagePercentage > 0 {
+		targetAvailable = int64(float64(size) * (100 - diskFillCfg.UsagePercentage) / 100)
+	}
+	return available - targetAvailable
+}
+
+// DiskThrottleCfg describes the limits of the disk io, zero means the limit is not set.
+type DiskThrottleCfg struct {
+	// bytes per second which can be read
+	ReadBps int64
+	// bytes per second which can be written
+	WriteBps int64
+	// read operations per second
+	ReadIops int64
+	// write operations per second
+	WriteIops int64
+}
+
+// Returns the limits in the format of the cgroup io.max file, e.g. 'rbps=1048576 wbps=max riops=max wiops=max'.
+func (cfg DiskThrottleCfg) String() string {
+	limits := []struct {
+		key   string
+		value int64
+	}{{"rbps", cfg.ReadBps}, {"wbps", cfg.WriteBps}, {"riops", cfg.ReadIops}, {"wiops", cfg.WriteIops}}
+
+	var formattedLimits []string
+	for _, limit := range limits {
+		value := "max"
+		if limit.value > 0 {
+			value = strconv.FormatInt(limit.value, 10)
+		}
+		formattedLimits = append(formattedLimits, fmt.Sprintf("%s=%s", limit.key, value))
+	}
+	return strings.Join(formattedLimits, " ")
+}
+
+// No limits, which is used to reset the throttling
+var noDiskLimits = DiskThrottleCfg{}
+
+// Throttles the io of the data volume of the given broker pod, and resets it after the given duration.
+// The limits are applied via the io.max file of the cgroup of the Zeebe container, which requires a privileged debug
+// container and cgroup v2.
+func ThrottleDiskOfPod(k8Client K8Client, podName string, diskThrottleCfg DiskThrottleCfg, duration time.Duration) error {
+	if diskThrottleCfg == noDiskLimits {
+		return errors.New("expected at least one disk io limit, but none was set")
+	}
+	if duration <= 0 {
+		return fmt.Errorf("expected a positive duration after which the disk io of %s is reset, but got %s", podName, duration)
+	}
+	return applyFault(k8Client, podName, toolSetup("util-linux"), buildIoMaxCommand(diskThrottleCfg), buildIoMaxCommand(noDiskLimits), duration)
+}
+
+// Builds the command to write the given limits into the io.max file of the cgroup of the Zeebe (java) process.
+//
+// The limits are set for the device of the data volume, which we look up in the mountinfo of the process. Since
+// io.max only accepts whole disks, the parent device is used for partitions. To access the cgroup of the process,
+// we enter its cgroup namespace and mount the cgroup2 file system, which has the cgroup of the process as root.
+func buildIoMaxCommand(diskThrottleCfg DiskThrottleCfg) string {
+	cgroupMount := "/tmp/zbchaos-cgroup"
+	return strings.Join([]string{
+		fmt.Sprintf(`pid=$(for p in /proc/[0-9]*; do if [ "$(cat $p/comm 2>/dev/null)" = "%s" ]; then echo ${p#/proc/}; break; fi; done)`, zeebeProcessName),
+		fmt.Sprintf(`[ -n "$pid" ] || { echo "no %s process found"; exit 1; }`, zeebeProcessName),
+		fmt.Sprintf(`dev=$(awk '$5 == "%s" {print $3}' /proc/$pid/mountinfo)`, zeebeDataDirectory),
+		fmt.Sprintf(`[ -n "$dev" ] || { echo "no device found for %s"; exit 1; }`, zeebeDataDirectory),
+		`if [ -e /sys/dev/block/$dev/partition ]; then dev=$(cat /sys/dev/block/$dev/../dev); fi`,
+		fmt.Sprintf(`nsenter -t $pid -C sh -c "mkdir -p %[1]s && (mountpoint -q %[1]s || mount -t cgroup2 none %[1]s) && echo '$dev %[2]s' > %[1]s/io.max"`,
+			cgroupMount, diskThrottleCfg),
+	}, "; ")
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldParseDfOutput(t *testing.T) {
+	// given
+	output := "   1B-blocks       Avail\r\n 10000000000  8000000000\r\n"
+
+	// when
+	size, available, err := parseDfOutput(output)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int64(10000000000), size)
+	assert.Equal(t, int64(8000000000), available)
+}
+
+func Test_ShouldFailOnInvalidDfOutput(t *testing.T) {
+	// given
+	output := "df: /usr/local/zeebe/data: No such file or directory"
+
+	// when
+	_, _, err := parseDfOutput(output)
+
+	// then
+	require.Error(t, err)
+}
+
+func Test_ShouldCalculateBallastSizeForUsagePercentage(t *testing.T) {
+	// given
+	diskFillCfg := DiskFillCfg{UsagePercentage: 95}
+
+	// when
+	ballastSize := calculateBallastSize(10000000000, 8000000000, diskFillCfg)
+
+	// then
+	assert.Equal(t, int64(7500000000), ballastSize)
+}
+
+func Test_ShouldCalculateBallastSizeForFreeBytes(t *testing.T) {
+	// given
+	diskFillCfg := DiskFillCfg{FreeBytes: 1000000000}
+
+	// when
+	ballastSize := calculateBallastSize(10000000000, 8000000000, diskFillCfg)
+
+	// then
+	assert.Equal(t, int64(7000000000), ballastSize)
+}
+
+func Test_ShouldNotFillIfThresholdIsReached(t *testing.T) {
+	// given
+	diskFillCfg := DiskFillCfg{UsagePercentage: 10}
+
+	// when
+	ballastSize := calculateBallastSize(10000000000, 8000000000, diskFillCfg)
+
+	// then
+	assert.LessOrEqual(t, ballastSize, int64(0))
+}
+
+func Test_ShouldRejectZeroUsagePercentage(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+
+	// when
+	_, err := FillDiskOfPod(k8Client, "zeebe-0", DiskFillCfg{UsagePercentage: 0})
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected either a usage percentage or free bytes greater than 0")
+}
+
+func Test_ShouldFormatDiskLimits(t *testing.T) {
+	// given
+	diskThrottleCfg := DiskThrottleCfg{WriteBps: 1048576, ReadIops: 100}
+
+	// when
+	limits := diskThrottleCfg.String()
+
+	// then
+	assert.Equal(t, "rbps=max wbps=1048576 riops=100 wiops=max", limits)
+}
+
+func Test_ShouldBuildIoMaxCommand(t *testing.T) {
+	// given
+	diskThrottleCfg := DiskThrottleCfg{ReadBps: 1048576}
+
+	// when
+	cmd := buildIoMaxCommand(diskThrottleCfg)
+
+	// then
+	assert.Contains(t, cmd, `dev=$(awk '$5 == "/usr/local/zeebe/data" {print $3}' /proc/$pid/mountinfo)`)
+	assert.Contains(t, cmd, `nsenter -t $pid -C sh -c`)
+	assert.Contains(t, cmd, `echo '$dev rbps=1048576 wbps=max riops=max wiops=max' > /tmp/zbchaos-cgroup/io.max`)
+}
+
+func Test_ShouldRejectDiskThrottleWithoutLimits(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+
+	// when
+	err := ThrottleDiskOfPod(k8Client, "zeebe-0", DiskThrottleCfg{}, time.Minute)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected at least one disk io limit")
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// The modes of a dns fault
+const (
+	// dns queries are dropped, which means lookups time out
+	DnsModeDrop = "drop"
+	// dns queries are rejected, which means lookups fail fast
+	DnsModeReject = "reject"
+	// lookups return NXDOMAIN, i.e. the hostname doesn't exist
+	DnsModeNxdomain = "nxdomain"
+)
+
+// The user of dnsmasq, after it dropped its privileges. Its queries are not redirected to itself.
+const dnsmasqUser = "nobody"
+
+// The name of the dnsmasq process, which is started for the nxdomain mode
+const dnsmasqProcessName = "dnsmasq"
+
+// Injects a dns fault for the given hostnames into the given pod. If a duration is given, the fault is healed afterwards.
+//
+// For the drop and reject mode, the dns queries which contain the hostname are matched via iptables. This means
+// partial hostnames (e.g. the service name) affect all queries containing them, independent of the search domains.
+// For the nxdomain mode, a dnsmasq is started in the pod which answers with NXDOMAIN for the hostnames and forwards all
+// other queries. Since dnsmasq matches the hostname and its sub domains, the hostname needs to be fully qualified,
+// e.g. zeebe-broker-service.namespace.svc.cluster.local.
+func InjectDnsFaultForPod(k8Client K8Client, podName string, hostnames []string, mode string, duration time.Duration) error {
+	if len(hostnames) == 0 {
+		return errors.New("expected at least one hostname for the dns fault")
+	}
+
+	switch mode {
+	case DnsModeDrop, DnsModeReject:
+		target := "DROP"
+		if mode == DnsModeReject {
+			target = "REJECT"
+		}
+		addRules, err := buildDnsMatchCommand("-A", hostnames, target)
+		if err != nil {
+			return err
+		}
+		deleteRules, _ := buildDnsMatchCommand("-D", hostnames, target)
+		return applyNetworkFault(k8Client, podName, toolSetup("iptables"), addRules, deleteRules, duration)
+	case DnsModeNxdomain:
+		port := rand.IntnRange(20000, 30000)
+		containerName := getZeebeContainerName(podName)
+		setupCmd := joinCommands(toolSetup("dnsmasq"), buildPortCheckCommand(port))
+		err := k8Client.StartCommandViaDebugContainer(podName, containerName, DebugImage, setupCmd, buildDnsmasqCommand(hostnames, port))
+		if err != nil {
+			return err
+		}
+
+		// the redirect might already be removed (e.g. via connect), but dnsmasq should be stopped anyway
+		healCmd := fmt.Sprintf("%s; %s", buildDnsRedirectCommand("-D", port), buildStopDnsmasqCommand(port))
+		err = applyNetworkFault(k8Client, podName, toolSetup("iptables"), buildDnsRedirectCommand("-A", port), healCmd, duration)
+		if err != nil {
+			stopErr := k8Client.ExecuteCommandViaDebugContainer(podName, containerName, DebugImage, []string{"sh", "-c", buildStopDnsmasqCommand(port)})
+			if stopErr != nil {
+				LogInfo("Failed to stop dnsmasq on port %d of %s, after the dns redirect failed. Error: %s", port, podName, stopErr.Error())
+			}
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("expected dns fault mode to be one of [%s, %s, %s], but got '%s'", DnsModeDrop, DnsModeReject, DnsModeNxdomain, mode)
+	}
+}
+
+// Builds the iptables commands to append (-A) or delete (-D) the rules, which apply the given target on outgoing dns
+// queries (udp and tcp) containing the given hostnames.
+func buildDnsMatchCommand(action string, hostnames []string, target string) (string, error) {
+	var cmds []string
+	for _, hostname := range hostnames {
+		hexHostname, err := encodeDnsHostname(hostname)
+		if err != nil {
+			return "", err
+		}
+		for _, protocol := range []string{"udp", "tcp"} {
+			cmds = append(cmds, fmt.Sprintf("iptables %s OUTPUT -p %s --dport 53 -m string --algo bm --icase --hex-string '|%s|' -m comment --comment %s -j %s",
+				action, protocol, hexHostname, iptablesComment, target))
+		}
+	}
+	return strings.Join(cmds, " && "), nil
+}
+
+// Encodes the hostname as it is contained in dns queries, where each label is prefixed with its length.
+// For example 'zeebe.svc' results in the hex string of '\x05zeebe\x03svc'.
+func encodeDnsHostname(hostname string) (string, error) {
+	builder := strings.Builder{}
+	for _, label := range strings.Split(strings.Trim(hostname, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return "", fmt.Errorf("expected valid hostname, but '%s' contains a label with invalid length", hostname)
+		}
+		builder.WriteString(fmt.Sprintf("%02x", len(label)))
+		builder.WriteString(hex.EncodeToString([]byte(label)))
+	}
+	return builder.String(), nil
+}
+
+// Builds the command to start dnsmasq on the given port, which answers with NXDOMAIN for the given hostnames and
+// forwards all other queries to the name server of the pod.
+func buildDnsmasqCommand(hostnames []string, port int) string {
+	args := []string{
+		dnsmasqProcessName, "--keep-in-foreground", "--no-resolv", "--no-hosts",
+		"--server=$(awk '/^nameserver/ {print $2; exit}' /etc/resolv.conf)",
+		"--listen-address=127.0.0.1", "--bind-interfaces", fmt.Sprintf("--port=%d", port), "--user=" + dnsmasqUser,
+	}
+	for _, hostname := range hostnames {
+		args = append(args, fmt.Sprintf("--address=/%s/", strings.Trim(hostname, ".")))
+	}
+	return strings.Join(args, " ")
+}
+
+// Builds the iptables commands to append (-A) or delete (-D) the rules, which redirect all outgoing dns queries
+// to the given port. The queries of dnsmasq itself are excluded, such that it can forward them.
+func buildDnsRedirectCommand(action string, port int) string {
+	var cmds []string
+	for _, protocol := range []string{"udp", "tcp"} {
+		cmds = append(cmds, fmt.Sprintf("iptables -t nat %s OUTPUT -p %s --dport 53 -m owner ! --uid-owner %s -m comment --comment %s -j REDIRECT --to-ports %d",
+			action, protocol, dnsmasqUser, iptablesComment, port))
+	}
+	return strings.Join(cmds, " && ")
+}
+
+// Builds the command which fails, if the given port is already used by a tcp or udp socket of the pod.
+// The ports are listed in hex in the socket tables, e.g. 0100007F:62E1 for 127.0.0.1:25313.
+func buildPortCheckCommand(port int) string {
+	return fmt.Sprintf(`{ ! grep -q ':%04X ' /proc/net/tcp /proc/net/udp /proc/net/tcp6 /proc/net/udp6 2> /dev/null || { echo "port %d is already in use"; exit 1; }; }`, port, port)
+}
+
+// Builds the command to stop the dnsmasq process which listens on the given port, or all dnsmasq processes if
+// the port is zero. The port is matched in the command line of the process, where the arguments are separated by NUL.
+func buildStopDnsmasqCommand(port int) string {
+	pattern := "*"
+	if port > 0 {
+		pattern = fmt.Sprintf(`*"--port=%d "*`, port)
+	}
+	return fmt.Sprintf(`for p in /proc/[0-9]*; do if [ "$(cat $p/comm 2>/dev/null)" = "%s" ]; then case "$(tr '\0' ' ' < $p/cmdline 2>/dev/null)" in %s) kill ${p#/proc/};; esac; fi; done`,
+		dnsmasqProcessName, pattern)
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldEncodeDnsHostname(t *testing.T) {
+	// given
+	hostname := "zeebe.svc."
+
+	// when
+	encoded, err := encodeDnsHostname(hostname)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "057a6565626503737663", encoded)
+}
+
+func Test_ShouldRejectInvalidDnsHostname(t *testing.T) {
+	// given
+	hostname := "zeebe.." + strings.Repeat("a", 64)
+
+	// when
+	_, err := encodeDnsHostname(hostname)
+
+	// then
+	require.Error(t, err)
+}
+
+func Test_ShouldBuildDnsMatchCommand(t *testing.T) {
+	// given
+	hostnames := []string{"zeebe"}
+
+	// when
+	cmd, err := buildDnsMatchCommand("-A", hostnames, "DROP")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "iptables -A OUTPUT -p udp --dport 53 -m string --algo bm --icase --hex-string '|057a65656265|' -m comment --comment zbchaos -j DROP && "+
+		"iptables -A OUTPUT -p tcp --dport 53 -m string --algo bm --icase --hex-string '|057a65656265|' -m comment --comment zbchaos -j DROP", cmd)
+}
+
+func Test_ShouldBuildDnsmasqCommand(t *testing.T) {
+	// given
+	hostnames := []string{"zeebe-broker-service.zeebe.svc.cluster.local"}
+
+	// when
+	cmd := buildDnsmasqCommand(hostnames, 25353)
+
+	// then
+	assert.Contains(t, cmd, "--port=25353")
+	assert.Contains(t, cmd, "--user=nobody")
+	assert.Contains(t, cmd, "--address=/zeebe-broker-service.zeebe.svc.cluster.local/")
+}
+
+func Test_ShouldBuildDnsRedirectCommand(t *testing.T) {
+	// given
+	port := 25353
+
+	// when
+	cmd := buildDnsRedirectCommand("-D", port)
+
+	// then
+	assert.Equal(t, "iptables -t nat -D OUTPUT -p udp --dport 53 -m owner ! --uid-owner nobody -m comment --comment zbchaos -j REDIRECT --to-ports 25353 && "+
+		"iptables -t nat -D OUTPUT -p tcp --dport 53 -m owner ! --uid-owner nobody -m comment --comment zbchaos -j REDIRECT --to-ports 25353", cmd)
+}
+
+func Test_ShouldBuildPortCheckCommand(t *testing.T) {
+	// given
+	port := 25353
+
+	// when
+	cmd := buildPortCheckCommand(port)
+
+	// then
+	assert.Equal(t, `{ ! grep -q ':6309 ' /proc/net/tcp /proc/net/udp /proc/net/tcp6 /proc/net/udp6 2> /dev/null || { echo "port 25353 is already in use"; exit 1; }; }`, cmd)
+}
+
+func Test_ShouldBuildStopDnsmasqCommandForPort(t *testing.T) {
+	// given
+	port := 25353
+
+	// when
+	cmd := buildStopDnsmasqCommand(port)
+
+	// then
+	assert.Contains(t, cmd, `[ "$(cat $p/comm 2>/dev/null)" = "dnsmasq" ]`)
+	assert.Contains(t, cmd, `in *"--port=25353 "*) kill ${p#/proc/};;`)
+}
+
+func Test_ShouldBuildStopDnsmasqCommandForAllPorts(t *testing.T) {
+	// given
+	port := 0
+
+	// when
+	cmd := buildStopDnsmasqCommand(port)
+
+	// then
+	assert.Contains(t, cmd, `in *) kill ${p#/proc/};;`)
+}
+
+func Test_ShouldRejectUnknownDnsMode(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+
+	// when
+	err := InjectDnsFaultForPod(k8Client, "zeebe-0", []string{"zeebe"}, "servfail", 0)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected dns fault mode")
+}
@@ -15,41 +15,295 @@
 package internal
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func MakeIpUnreachableForPod(k8Client K8Client, podIp string, podName string) error {
-	cmd := []string{"ip", "route", "replace", "unreachable", podIp}
-	cmdWithSetup := []string{"sh", "-c", "apt update && apt install -y iproute2 && " + strings.Join(cmd, " ")}
-	var containerName string
-	if strings.Contains(podName, "gateway") {
-		containerName = "zeebe-gateway"
-	} else {
-		containerName = "zeebe"
-	}
-	return k8Client.ExecuteCommandViaDebugContainer(podName, containerName, "camunda/zeebe", cmdWithSetup)
+// The network device of the pod, on which traffic control rules are installed
+const networkDevice = "eth0"
+
+// Makes the given ip unreachable for the given pod. If a duration is given, the route is removed again afterwards.
+func MakeIpUnreachableForPod(k8Client K8Client, podIp string, podName string, duration time.Duration) error {
+	return applyNetworkFault(k8Client, podName, toolSetup("iproute2"), "ip route replace unreachable "+podIp, "ip route del unreachable "+podIp, duration)
 }
 
+// Removes all unreachable routes of the given pod, which might have been added towards multiple ips.
 func MakeIpReachableForPod(k8Client K8Client, podName string) error {
-	cmd := "ip route del $(ip route | grep -m 1 unreachable)"
-	cmdWithSetup := []string{"sh", "-c", "apt update && apt install -y iproute2 && " + cmd}
-	var containerName string
-	if strings.Contains(podName, "gateway") {
-		containerName = "zeebe-gateway"
-	} else {
-		containerName = "zeebe"
-	}
-	return k8Client.ExecuteCommandViaDebugContainer(podName, containerName, "camunda/zeebe", cmdWithSetup)
+	cmd := "ip route show type unreachable | while read -r route; do ip route del $route; done"
+	cmdWithSetup := []string{"sh", "-c", withToolSetup(cmd, "iproute2")}
+	return k8Client.ExecuteCommandViaDebugContainer(podName, getZeebeContainerName(podName), DebugImage, cmdWithSetup)
 }
 
 func MakeIpReachable(k8Client K8Client, podName string, ip string) error {
 	cmd := "ip route del unreachable " + ip
-	cmdWithSetup := []string{"sh", "-c", "apt update && apt install -y iproute2 && " + cmd}
-	var containerName string
+	cmdWithSetup := []string{"sh", "-c", withToolSetup(cmd, "iproute2")}
+	return k8Client.ExecuteCommandViaDebugContainer(podName, getZeebeContainerName(podName), DebugImage, cmdWithSetup)
+}
+
+// The comment which is attached to all iptables rules of zbchaos, to find and remove them again
+const iptablesComment = "zbchaos"
+
+// Drops the tcp traffic of the given pod towards the given ip, on the given ports only. Other traffic,
+// e.g. on other ports, is still possible. If a duration is given, the rules are removed again afterwards.
+func BlockPortsForPod(k8Client K8Client, podIp string, podName string, ports []int, duration time.Duration) error {
+	return applyNetworkFault(k8Client, podName, toolSetup("iptables"), buildPortsCommand("-A", podIp, ports), buildPortsCommand("-D", podIp, ports), duration)
+}
+
+// Builds the iptables commands to append (-A) or delete (-D) the rules, which drop outgoing tcp traffic towards
+// the given ip on the given ports.
+//
+// We drop packets with the given destination port, which are the requests to the other pod, as well as packets with
+// the given source port, which are the responses to requests the other pod sent us. This makes sure that already
+// established connections are affected as well.
+func buildPortsCommand(action string, podIp string, ports []int) string {
+	portList := make([]string, len(ports))
+	for i, port := range ports {
+		portList[i] = strconv.Itoa(port)
+	}
+	joinedPorts := strings.Join(portList, ",")
+
+	var cmds []string
+	for _, portOption := range []string{"--dports", "--sports"} {
+		cmds = append(cmds, fmt.Sprintf("iptables %s OUTPUT -p tcp -d %s -m multiport %s %s -m comment --comment %s -j DROP",
+			action, podIp, portOption, joinedPorts, iptablesComment))
+	}
+	return strings.Join(cmds, " && ")
+}
+
+// Builds the command to delete all zbchaos rules of the filter and nat table, by listing them and replacing the
+// append with a delete. The rules are evaluated, since they might contain quoted arguments (e.g. hex strings).
+func buildRemoveIptablesRulesCommand() string {
+	var cmds []string
+	for _, table := range []string{"filter", "nat"} {
+		cmds = append(cmds, fmt.Sprintf("iptables -t %[1]s -S OUTPUT | grep -- '--comment %[2]s' | sed 's/^-A/-D/' | while read -r rule; do eval \"iptables -t %[1]s $rule\"; done",
+			table, iptablesComment))
+	}
+	return strings.Join(cmds, " && ")
+}
+
+// NetemCfg describes the impairment which is applied via netem on the affected traffic.
+// Empty delays and zero percentages are not applied.
+type NetemCfg struct {
+	// fixed latency which is added to each packet, e.g. 100ms
+	Delay string
+	// variation of the latency, e.g. 10ms
+	Jitter string
+	// percentage of packets which are dropped
+	Loss float64
+	// percentage of packets which are duplicated
+	Duplicate float64
+	// percentage of packets which are sent immediately, all others are delayed. Requires a delay.
+	Reorder float64
+	// percentage of packets in which a single bit is flipped
+	Corrupt float64
+}
+
+// Returns the netem qdisc arguments for the configuration, e.g. 'netem delay 100ms 10ms loss 5%'
+func (cfg NetemCfg) String() string {
+	args := []string{"netem"}
+	if cfg.Delay != "" {
+		args = append(args, "delay", cfg.Delay)
+		if cfg.Jitter != "" {
+			args = append(args, cfg.Jitter)
+		}
+	}
+	if cfg.Loss > 0 {
+		args = append(args, "loss", formatPercentage(cfg.Loss))
+	}
+	if cfg.Duplicate > 0 {
+		args = append(args, "duplicate", formatPercentage(cfg.Duplicate))
+	}
+	if cfg.Reorder > 0 {
+		args = append(args, "reorder", formatPercentage(cfg.Reorder))
+	}
+	if cfg.Corrupt > 0 {
+		args = append(args, "corrupt", formatPercentage(cfg.Corrupt))
+	}
+	return strings.Join(args, " ")
+}
+
+func (cfg NetemCfg) validate() error {
+	for _, percentage := range []float64{cfg.Loss, cfg.Duplicate, cfg.Reorder, cfg.Corrupt} {
+		if percentage < 0 || percentage > 100 {
+			return fmt.Errorf("expected percentage to be between 0 and 100, but got %v", percentage)
+		}
+	}
+	if cfg.Reorder > 0 && cfg.Delay == "" {
+		return errors.New("expected a delay to be set, since reordering packets requires a delay")
+	}
+	return nil
+}
+
+func formatPercentage(percentage float64) string {
+	return strconv.FormatFloat(percentage, 'f', -1, 64) + "%"
+}
+
+// Applies the given netem configuration on all traffic from the given pod to the given ip.
+// If a duration is given, the traffic control rules of this fault are removed again afterwards.
+func AddNetemForPod(k8Client K8Client, podIp string, podName string, netemCfg NetemCfg, duration time.Duration) error {
+	err := netemCfg.validate()
+	if err != nil {
+		return err
+	}
+
+	return applyTrafficControlFault(k8Client, podName, netemCfg.String(), []string{podIp}, duration)
+}
+
+// Limits the egress bandwidth of the given pod via a token bucket filter (tbf), e.g. to 1mbit.
+// If target ips are given, only the traffic towards these ips is throttled, otherwise all egress traffic of the pod.
+// The burst specifies the size of the bucket, e.g. 32kbit. Higher rates require bigger buckets.
+// If a duration is given, the traffic control rules of this fault are removed again afterwards.
+func ThrottleBandwidthForPod(k8Client K8Client, podName string, rate string, burst string, targetIps []string, duration time.Duration) error {
+	qdisc := fmt.Sprintf("tbf rate %s burst %s latency 400ms", rate, burst)
+	return applyTrafficControlFault(k8Client, podName, qdisc, targetIps, duration)
+}
+
+// Removes the network faults of zbchaos from the given pod with one debug container: the unreachable routes, the
+// traffic control rules, the iptables rules and the dnsmasq processes of nxdomain dns faults. The pending heals of
+// network faults are stopped before, since they could remove later faults otherwise. If ips are given, only
+// the unreachable routes towards them are removed. All removals are tried, even if one of them fails.
+func RemoveNetworkFaultsForPod(k8Client K8Client, podName string, unreachableIps []string) error {
+	cmdWithSetup := []string{"sh", "-c", withToolSetup(buildRemoveNetworkFaultsCommand(unreachableIps), "iproute2", "iptables")}
+	return k8Client.ExecuteCommandViaDebugContainer(podName, getZeebeContainerName(podName), DebugImage, cmdWithSetup)
+}
+
+func buildRemoveNetworkFaultsCommand(unreachableIps []string) string {
+	removeRoutes := "ip route show type unreachable | while read -r route; do ip route del $route; done"
+	if len(unreachableIps) > 0 {
+		// the routes might not exist, e.g. if the pod was never disconnected from the ip
+		removeRoutes = fmt.Sprintf("for ip in %s; do ip route del unreachable $ip 2> /dev/null; done; true", strings.Join(unreachableIps, " "))
+	}
+	// the prio qdisc of zbchaos only exists if a traffic control fault has been applied, the default root qdisc can't be deleted
+	removeTrafficControl := fmt.Sprintf("if tc qdisc show dev %[1]s | grep -q '^qdisc prio 1: root'; then tc qdisc del dev %[1]s root; fi", networkDevice)
+
+	var cmds []string
+	for _, cmd := range []string{buildStopPendingNetworkHealsCommand(), removeRoutes, removeTrafficControl, buildRemoveIptablesRulesCommand(), buildStopDnsmasqCommand(0)} {
+		cmds = append(cmds, fmt.Sprintf("{ %s; } || status=1", cmd))
+	}
+	return "status=0; " + strings.Join(cmds, "; ") + "; exit $status"
+}
+
+// Applies the traffic control fault like applyFault, but the heal command is built after the fault is applied, since
+// it depends on the band which has been chosen for the fault. This way only the rules of this fault are removed.
+func applyTrafficControlFault(k8Client K8Client, podName string, qdisc string, targetIps []string, duration time.Duration) error {
+	containerName := getZeebeContainerName(podName)
+	setupCmd := toolSetup("iproute2")
+	cmd := []string{"sh", "-c", joinCommands(setupCmd, buildTrafficControlCommand(qdisc, targetIps))}
+	output, err := k8Client.ExecuteCommandViaDebugContainerWithOutput(podName, containerName, DebugImage, cmd, debugContainerTimeout)
+	if err != nil || duration <= 0 {
+		return err
+	}
+
+	band, err := parseTrafficControlBand(output)
+	if err != nil {
+		return err
+	}
+	return k8Client.StartCommandViaDebugContainer(podName, containerName, DebugImage, setupCmd, healNetworkFaultAfter(buildRemoveTrafficControlBandCommand(band), duration))
+}
+
+// Applies the fault via the given command in a debug container of the pod, and waits until it is applied.
+// If a duration is given, another debug container is started which heals the fault after the duration. Since it runs
+// in the pod, the fault is healed even if zbchaos is no longer running, e.g. because it crashed.
+func applyFault(k8Client K8Client, podName string, setupCmd string, cmd string, healCmd string, duration time.Duration) error {
+	return applyFaultWithDelayedHeal(k8Client, podName, setupCmd, cmd, healAfter(healCmd, duration), duration)
+}
+
+// Applies the network fault like applyFault, but marks the pending heal, such that connect can stop it.
+func applyNetworkFault(k8Client K8Client, podName string, setupCmd string, cmd string, healCmd string, duration time.Duration) error {
+	return applyFaultWithDelayedHeal(k8Client, podName, setupCmd, cmd, healNetworkFaultAfter(healCmd, duration), duration)
+}
+
+func applyFaultWithDelayedHeal(k8Client K8Client, podName string, setupCmd string, cmd string, delayedHealCmd string, duration time.Duration) error {
+	containerName := getZeebeContainerName(podName)
+	err := k8Client.ExecuteCommandViaDebugContainer(podName, containerName, DebugImage, []string{"sh", "-c", joinCommands(setupCmd, cmd)})
+	if err != nil || duration <= 0 {
+		return err
+	}
+	return k8Client.StartCommandViaDebugContainer(podName, containerName, DebugImage, setupCmd, delayedHealCmd)
+}
+
+// Returns the command which runs the heal command after the given duration.
+func healAfter(healCmd string, duration time.Duration) string {
+	return fmt.Sprintf("sleep %s && %s", strconv.FormatFloat(duration.Seconds(), 'f', -1, 64), healCmd)
+}
+
+// The marker of the pending heals of network faults. The heals only remove the rules of their own fault, but after
+// connect removed all network faults, a later fault might get the same rules (e.g. the same traffic control band).
+// Connect therefore stops the pending heals, which are found via this marker.
+const pendingNetworkHealMarker = "zbchaos-network-heal"
+
+// Returns the command which runs the heal command of a network fault after the given duration, marked as pending heal.
+func healNetworkFaultAfter(healCmd string, duration time.Duration) string {
+	return fmt.Sprintf(": %s; %s", pendingNetworkHealMarker, healAfter(healCmd, duration))
+}
+
+// Builds the command which kills the shells of the pending network heals, before they run the heal command.
+// The marker is split in the pattern, such that the command doesn't match its own shell.
+func buildStopPendingNetworkHealsCommand() string {
+	pattern := fmt.Sprintf(`*"%s""%s"*`, pendingNetworkHealMarker[:1], pendingNetworkHealMarker[1:])
+	return fmt.Sprintf(`for p in /proc/[0-9]*; do case "$(tr '\0' ' ' < $p/cmdline 2>/dev/null)" in %s) kill -9 ${p#/proc/};; esac; done; true`, pattern)
+}
+
+// The bands of our prio qdisc, which are used for the faults. The default priomap only uses the first three bands, and
+// a prio qdisc supports at most 16 bands.
+const (
+	firstTrafficControlBand = 4
+	lastTrafficControlBand  = 16
+)
+
+// The marker which is printed, after a traffic control fault has been installed on a band
+const trafficControlBandMarker = "zbchaos: traffic control band "
+
+// Builds the tc command to install the given queueing discipline (e.g. netem) for all traffic towards the given ips.
+//
+// The fault is not installed as root qdisc, since this would affect all traffic of the pod. Instead, we install a
+// prio qdisc with additional bands, which are not used by the default priomap. Each fault gets its own band, the
+// first one without a qdisc of zbchaos, such that faults can be applied one after the other without changing each
+// other. The given qdisc is attached to this band and only traffic matching the destination filters is routed through it.
+// The filters use the band as priority, which allows to remove the filters of a single fault.
+// The prio qdisc is only added if it doesn't exist yet. If no target ips are given, all ip traffic is routed through
+// the given qdisc. The chosen band is printed with the trafficControlBandMarker.
+func buildTrafficControlCommand(qdisc string, targetIps []string) string {
+	commands := []string{
+		fmt.Sprintf("(tc qdisc show dev %[1]s | grep -q 'qdisc prio 1: root' || tc qdisc add dev %[1]s root handle 1: prio bands %[2]d)", networkDevice, lastTrafficControlBand),
+		// the minor of the class ids and handles is hex, e.g. band 10 results in class 1:a and handle a0:
+		fmt.Sprintf(`band=$(for b in $(seq %[2]d %[3]d); do m=$(printf %%x $b); tc qdisc show dev %[1]s | grep -q " ${m}0: parent 1:$m " || { echo $b; break; }; done)`,
+			networkDevice, firstTrafficControlBand, lastTrafficControlBand),
+		`{ [ -n "$band" ] || { echo "no free traffic control band left, remove other traffic control faults first"; exit 1; }; }`,
+		"minor=$(printf %x $band)",
+		fmt.Sprintf("tc qdisc add dev %s parent 1:$minor handle ${minor}0: %s", networkDevice, qdisc),
+	}
+	if len(targetIps) == 0 {
+		commands = append(commands, fmt.Sprintf("tc filter add dev %s parent 1: protocol ip prio $band u32 match ip dst 0.0.0.0/0 flowid 1:$minor", networkDevice))
+	}
+	for _, ip := range targetIps {
+		commands = append(commands, fmt.Sprintf("tc filter add dev %s parent 1: protocol ip prio $band u32 match ip dst %s/32 flowid 1:$minor", networkDevice, ip))
+	}
+	commands = append(commands, fmt.Sprintf(`echo "%s$band"`, trafficControlBandMarker))
+	return strings.Join(commands, " && ")
+}
+
+// Returns the band which has been printed by the traffic control command.
+func parseTrafficControlBand(output string) (int, error) {
+	for _, line := range strings.Split(output, "\n") {
+		if band, found := strings.CutPrefix(strings.TrimSpace(line), trafficControlBandMarker); found {
+			return strconv.Atoi(band)
+		}
+	}
+	return 0, fmt.Errorf("expected the traffic control band in the output, but got: %s", output)
+}
+
+// Builds the command to remove the filters and the qdisc of the given band, other faults are kept.
+func buildRemoveTrafficControlBandCommand(band int) string {
+	return fmt.Sprintf("{ tc filter del dev %[1]s parent 1: protocol ip prio %[2]d; tc qdisc del dev %[1]s parent 1:%[2]x; }", networkDevice, band)
+}
+
+func getZeebeContainerName(podName string) string {
 	if strings.Contains(podName, "gateway") {
-		containerName = "zeebe-gateway"
-	} else {
-		containerName = "zeebe"
+		return "zeebe-gateway"
 	}
-	return k8Client.ExecuteCommandViaDebugContainer(podName, containerName, "camunda/zeebe", cmdWithSetup)
+	return "zeebe"
 }
@@ -0,0 +1,291 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The ip which is used by the traffic control filters, if all traffic of the pod is affected
+const AllTrafficIp = "0.0.0.0/0"
+
+// Markers to separate the output of the different tools in the debug container logs
+const (
+	routesSection   = "# routes"
+	qdiscsSection   = "# qdiscs"
+	filtersSection  = "# filters"
+	iptablesSection = "# iptables"
+	natSection      = "# nat"
+)
+
+// NetworkFaults describes the network faults, which are currently installed on a pod.
+type NetworkFaults struct {
+	// the ips which are unreachable for the pod
+	UnreachableIps []string
+	// the traffic control faults, ordered by their band
+	TrafficControl []TrafficControlFault
+	// the comma separated ports which are blocked, per ip
+	BlockedPorts map[string]string
+	// the dns faults of the drop and reject mode, which match the queries of a hostname
+	DnsFaults []DnsFault
+	// the ports of the dnsmasq processes, to which the dns queries are redirected (nxdomain dns faults)
+	DnsRedirectPorts []string
+}
+
+// DnsFault describes a dns fault of the drop or reject mode, which is applied to the dns queries of a hostname.
+type DnsFault struct {
+	// the mode of the fault, DnsModeDrop or DnsModeReject
+	Mode string
+	// the hostname whose queries are matched, e.g. zeebe-broker-service
+	Hostname string
+}
+
+// TrafficControlFault describes a queueing discipline, which is attached to one band of the prio qdisc of zbchaos.
+type TrafficControlFault struct {
+	// the queueing discipline, e.g. 'netem limit 1000 delay 100ms'
+	Qdisc string
+	// the ips whose traffic is routed through the queueing discipline, AllTrafficIp if all traffic is affected
+	Ips []string
+}
+
+// Collects the network faults (unreachable routes, traffic control and iptables rules) which are installed on the given pod.
+func GetNetworkFaultsForPod(k8Client K8Client, podName string) (NetworkFaults, error) {
+	sections := []string{
+		fmt.Sprintf("echo '%s'; ip route show type unreachable", routesSection),
+		fmt.Sprintf("echo '%s'; tc qdisc show dev %s", qdiscsSection, networkDevice),
+		// fails if no prio qdisc is installed, which just means there are no filters
+		fmt.Sprintf("echo '%s'; tc filter show dev %s parent 1: 2> /dev/null", filtersSection, networkDevice),
+		fmt.Sprintf("echo '%s'; iptables -S OUTPUT", iptablesSection),
+		fmt.Sprintf("echo '%s'; iptables -t nat -S OUTPUT", natSection),
+	}
+	cmd := strings.Join(sections, "; ")
+	if setup := toolSetup("iproute2", "iptables"); setup != "" {
+		// the output of the installation is discarded, since we parse the output of the tools
+		cmd = fmt.Sprintf("(%s) > /dev/null 2>&1 && { %s; }", setup, cmd)
+	}
+	cmdWithSetup := []string{"sh", "-c", cmd}
+	output, err := k8Client.ExecuteCommandViaDebugContainerWithOutput(podName, getZeebeContainerName(podName), DebugImage, cmdWithSetup, debugContainerTimeout)
+	if err != nil {
+		return NetworkFaults{}, err
+	}
+	return parseNetworkFaults(output), nil
+}
+
+func parseNetworkFaults(output string) NetworkFaults {
+	faults := NetworkFaults{BlockedPorts: map[string]string{}}
+	qdiscs := map[int]string{}
+	ips := map[int][]string{}
+	var bands []int
+	// the band of the filter, to which the following matches belong
+	filterBand := 0
+	section := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# ") {
+			section = line
+			continue
+		}
+		if line == "" {
+			continue
+		}
+
+		switch section {
+		case routesSection:
+			// e.g. 'unreachable 10.0.0.5'
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && fields[0] == "unreachable" {
+				faults.UnreachableIps = append(faults.UnreachableIps, fields[1])
+			}
+		case qdiscsSection:
+			if band, qdisc, ok := parseQdisc(line); ok {
+				qdiscs[band] = qdisc
+				bands = append(bands, band)
+			}
+		case filtersSection:
+			if strings.HasPrefix(line, "filter ") {
+				filterBand, _ = parseFilterFlowId(line)
+			} else if ip, ok := parseFilterMatch(line); ok && filterBand > 0 {
+				ips[filterBand] = append(ips[filterBand], ip)
+			}
+		case iptablesSection:
+			if ip, ports, ok := parseIptablesRule(line); ok {
+				faults.BlockedPorts[ip] = ports
+			} else if dnsFault, ok := parseDnsMatchRule(line); ok && !slices.Contains(faults.DnsFaults, dnsFault) {
+				// the queries are matched via udp and tcp with the same hostname
+				faults.DnsFaults = append(faults.DnsFaults, dnsFault)
+			}
+		case natSection:
+			// the queries are redirected via udp and tcp to the same port
+			if port, ok := parseDnsRedirectRule(line); ok && !slices.Contains(faults.DnsRedirectPorts, port) {
+				faults.DnsRedirectPorts = append(faults.DnsRedirectPorts, port)
+			}
+		}
+	}
+
+	sort.Ints(bands)
+	for _, band := range bands {
+		faults.TrafficControl = append(faults.TrafficControl, TrafficControlFault{Qdisc: qdiscs[band], Ips: ips[band]})
+	}
+	return faults
+}
+
+// Parses the qdisc which is attached to a fault band of our prio qdisc, e.g. 'qdisc netem 40: parent 1:4 limit 1000 delay 100ms'
+// results in band 4 and 'netem limit 1000 delay 100ms'. The minor of the class id is hex, e.g. 1:a for band 10.
+func parseQdisc(line string) (int, string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "qdisc" || fields[3] != "parent" || !strings.HasPrefix(fields[4], "1:") {
+		return 0, "", false
+	}
+
+	minor := strings.TrimPrefix(fields[4], "1:")
+	band, err := strconv.ParseInt(minor, 16, 0)
+	if err != nil || band < firstTrafficControlBand || fields[2] != minor+"0:" {
+		return 0, "", false
+	}
+	return int(band), strings.Join(append([]string{fields[1]}, fields[5:]...), " "), true
+}
+
+// Parses the band to which a filter routes the traffic,
+// e.g. 'filter parent 1: protocol ip pref 4 u32 chain 0 fh 800::800 order 2048 key ht 800 bkt 0 flowid 1:4' results in 4.
+func parseFilterFlowId(line string) (int, bool) {
+	fields := strings.Fields(line)
+	for i := 0; i < len(fields)-1; i++ {
+		if fields[i] == "flowid" && strings.HasPrefix(fields[i+1], "1:") {
+			band, err := strconv.ParseInt(strings.TrimPrefix(fields[i+1], "1:"), 16, 0)
+			return int(band), err == nil
+		}
+	}
+	return 0, false
+}
+
+// Parses the destination ip of an u32 filter match, e.g. 'match 0a000005/ffffffff at 16' results in '10.0.0.5'.
+// The destination ip is at offset 16 of the ip header. A match on all traffic results in AllTrafficIp.
+func parseFilterMatch(line string) (string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 || fields[0] != "match" || fields[2] != "at" || fields[3] != "16" {
+		return "", false
+	}
+
+	valueAndMask := strings.Split(fields[1], "/")
+	if len(valueAndMask) != 2 {
+		return "", false
+	}
+	if valueAndMask[1] == "00000000" {
+		return AllTrafficIp, true
+	}
+
+	ip, err := hex.DecodeString(valueAndMask[0])
+	if err != nil || len(ip) != net.IPv4len {
+		return "", false
+	}
+	return net.IP(ip).String(), true
+}
+
+// Parses a zbchaos iptables rule, e.g. '-A OUTPUT -d 10.0.0.5/32 -p tcp -m multiport --dports 26501 -m comment --comment zbchaos -j DROP'
+// results in '10.0.0.5' and '26501'. Rules which haven't been added by zbchaos are ignored.
+func parseIptablesRule(line string) (string, string, bool) {
+	fields := strings.Fields(line)
+	ip := ""
+	ports := ""
+	isZbchaosRule := false
+	for i := 0; i < len(fields)-1; i++ {
+		switch fields[i] {
+		case "-d":
+			ip = strings.TrimSuffix(fields[i+1], "/32")
+		case "--dports", "--sports":
+			ports = fields[i+1]
+		case "--comment":
+			isZbchaosRule = strings.Trim(fields[i+1], "\"") == iptablesComment
+		}
+	}
+	if !isZbchaosRule || ip == "" || ports == "" {
+		return "", "", false
+	}
+	return ip, ports, true
+}
+
+// Parses a zbchaos dns rule of the drop or reject mode, e.g.
+// '-A OUTPUT -p udp -m udp --dport 53 -m string --hex-string "|057a65656265|" --algo bm --icase -m comment --comment zbchaos -j DROP'
+// results in mode drop and hostname 'zeebe'. Rules which haven't been added by zbchaos are ignored.
+func parseDnsMatchRule(line string) (DnsFault, bool) {
+	fields := strings.Fields(line)
+	var pattern []byte
+	mode := ""
+	isZbchaosRule := false
+	for i := 0; i < len(fields)-1; i++ {
+		switch fields[i] {
+		case "--hex-string":
+			// iptables prints the pattern as hex, since the label lengths are not printable
+			pattern, _ = hex.DecodeString(strings.Trim(fields[i+1], "\"|"))
+		case "--string":
+			pattern = []byte(strings.Trim(fields[i+1], "\""))
+		case "-j":
+			switch fields[i+1] {
+			case "DROP":
+				mode = DnsModeDrop
+			case "REJECT":
+				mode = DnsModeReject
+			}
+		case "--comment":
+			isZbchaosRule = strings.Trim(fields[i+1], "\"") == iptablesComment
+		}
+	}
+	hostname, ok := decodeDnsHostname(pattern)
+	if !isZbchaosRule || mode == "" || !ok {
+		return DnsFault{}, false
+	}
+	return DnsFault{Mode: mode, Hostname: hostname}, true
+}
+
+// Decodes the hostname of a dns query, where each label is prefixed with its length (see encodeDnsHostname).
+func decodeDnsHostname(pattern []byte) (string, bool) {
+	var labels []string
+	for len(pattern) > 0 {
+		length := int(pattern[0])
+		if length == 0 || length >= len(pattern) {
+			return "", false
+		}
+		labels = append(labels, string(pattern[1:length+1]))
+		pattern = pattern[length+1:]
+	}
+	return strings.Join(labels, "."), len(labels) > 0
+}
+
+// Parses a zbchaos dns redirect rule of the nat table,
+// e.g. '-A OUTPUT -p udp -m udp --dport 53 -m owner ! --uid-owner 65534 -m comment --comment zbchaos -j REDIRECT --to-ports 25353'
+// results in '25353'. Rules which haven't been added by zbchaos are ignored.
+func parseDnsRedirectRule(line string) (string, bool) {
+	fields := strings.Fields(line)
+	port := ""
+	isZbchaosRule := false
+	for i := 0; i < len(fields)-1; i++ {
+		switch fields[i] {
+		case "--to-ports":
+			port = fields[i+1]
+		case "--comment":
+			isZbchaosRule = strings.Trim(fields[i+1], "\"") == iptablesComment
+		}
+	}
+	if !isZbchaosRule || port == "" {
+		return "", false
+	}
+	return port, true
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldParseNetworkFaults(t *testing.T) {
+	// given
+	output := `# routes
+unreachable 10.0.0.5
+unreachable 10.0.0.6
+# qdiscs
+qdisc prio 1: root refcnt 2 bands 16 priomap 1 2 2 2 1 2 0 0 1 1 1 1 1 1 1 1
+qdisc tbf a0: parent 1:a rate 1Mbit burst 4Kb lat 400ms
+qdisc netem 40: parent 1:4 limit 1000 delay 100ms  10ms
+# filters
+filter parent 1: protocol ip pref 4 u32 chain 0
+filter parent 1: protocol ip pref 4 u32 chain 0 fh 800: ht divisor 1
+filter parent 1: protocol ip pref 4 u32 chain 0 fh 800::800 order 2048 key ht 800 bkt 0 flowid 1:4 not_in_hw
+  match 0a000007/ffffffff at 16
+filter parent 1: protocol ip pref 4 u32 chain 0 fh 800::801 order 2049 key ht 800 bkt 0 flowid 1:4 not_in_hw
+  match 0a00000a/ffffffff at 16
+filter parent 1: protocol ip pref 10 u32 chain 0
+filter parent 1: protocol ip pref 10 u32 chain 0 fh 801: ht divisor 1
+filter parent 1: protocol ip pref 10 u32 chain 0 fh 801::800 order 2048 key ht 801 bkt 0 flowid 1:a not_in_hw
+  match 00000000/00000000 at 16
+# iptables
+-P OUTPUT ACCEPT
+-A OUTPUT -d 10.0.0.8/32 -p tcp -m multiport --dports 26501,26502 -m comment --comment zbchaos -j DROP
+-A OUTPUT -d 10.0.0.8/32 -p tcp -m multiport --sports 26501,26502 -m comment --comment zbchaos -j DROP
+-A OUTPUT -d 10.0.0.9/32 -p tcp -j DROP
+-A OUTPUT -p udp -m udp --dport 53 -m string --hex-string "|147a656562652d62726f6b65722d73657276696365|" --algo bm --icase -m comment --comment zbchaos -j DROP
+-A OUTPUT -p tcp -m tcp --dport 53 -m string --hex-string "|147a656562652d62726f6b65722d73657276696365|" --algo bm --icase -m comment --comment zbchaos -j DROP
+-A OUTPUT -p udp -m udp --dport 53 -m string --hex-string "|057a6565626503737663|" --algo bm --icase -m comment --comment zbchaos -j REJECT --reject-with icmp-port-unreachable
+-A OUTPUT -p udp -m udp --dport 53 -m string --hex-string "|057a6565626503737663|" --algo bm --icase -j DROP
+# nat
+-P OUTPUT ACCEPT
+-A OUTPUT -p udp -m udp --dport 53 -m owner ! --uid-owner 65534 -m comment --comment zbchaos -j REDIRECT --to-ports 25353
+-A OUTPUT -p tcp -m tcp --dport 53 -m owner ! --uid-owner 65534 -m comment --comment zbchaos -j REDIRECT --to-ports 25353
+-A OUTPUT -p udp -m udp --dport 53 -j REDIRECT --to-ports 5353
+`
+
+	// when
+	faults := parseNetworkFaults(output)
+
+	// then
+	assert.Equal(t, []string{"10.0.0.5", "10.0.0.6"}, faults.UnreachableIps)
+	assert.Equal(t, []TrafficControlFault{
+		{Qdisc: "netem limit 1000 delay 100ms 10ms", Ips: []string{"10.0.0.7", "10.0.0.10"}},
+		{Qdisc: "tbf rate 1Mbit burst 4Kb lat 400ms", Ips: []string{AllTrafficIp}},
+	}, faults.TrafficControl)
+	assert.Equal(t, map[string]string{"10.0.0.8": "26501,26502"}, faults.BlockedPorts)
+	assert.Equal(t, []DnsFault{
+		{Mode: DnsModeDrop, Hostname: "zeebe-broker-service"},
+		{Mode: DnsModeReject, Hostname: "zeebe.svc"},
+	}, faults.DnsFaults)
+	assert.Equal(t, []string{"25353"}, faults.DnsRedirectPorts)
+}
+
+func Test_ShouldParseDnsMatchRuleOfEncodedHostname(t *testing.T) {
+	// given
+	hexHostname, err := encodeDnsHostname("zeebe-broker-service.namespace.svc.cluster.local")
+	require.NoError(t, err)
+	line := `-A OUTPUT -p tcp -m tcp --dport 53 -m string --hex-string "|` + hexHostname + `|" --algo bm --icase -m comment --comment zbchaos -j DROP`
+
+	// when
+	dnsFault, ok := parseDnsMatchRule(line)
+
+	// then
+	assert.True(t, ok)
+	assert.Equal(t, DnsFault{Mode: DnsModeDrop, Hostname: "zeebe-broker-service.namespace.svc.cluster.local"}, dnsFault)
+}
+
+func Test_ShouldNotParseDnsMatchRuleWithInvalidHostname(t *testing.T) {
+	// given
+	line := `-A OUTPUT -p udp -m udp --dport 53 -m string --hex-string "|0a7a65656265|" --algo bm --icase -m comment --comment zbchaos -j DROP`
+
+	// when
+	_, ok := parseDnsMatchRule(line)
+
+	// then
+	assert.False(t, ok)
+}
+
+func Test_ShouldParseNoNetworkFaults(t *testing.T) {
+	// given
+	output := `# routes
+# qdiscs
+qdisc noqueue 0: root refcnt 2
+# filters
+# iptables
+-P OUTPUT ACCEPT
+# nat
+-P OUTPUT ACCEPT
+`
+
+	// when
+	faults := parseNetworkFaults(output)
+
+	// then
+	assert.Empty(t, faults.UnreachableIps)
+	assert.Empty(t, faults.TrafficControl)
+	assert.Empty(t, faults.BlockedPorts)
+	assert.Empty(t, faults.DnsFaults)
+	assert.Empty(t, faults.DnsRedirectPorts)
+}
+
+func Test_ShouldParseFilterMatchOnAllTraffic(t *testing.T) {
+	// given
+	line := "match 00000000/00000000 at 16"
+
+	// when
+	ip, ok := parseFilterMatch(line)
+
+	// then
+	assert.True(t, ok)
+	assert.Equal(t, AllTrafficIp, ip)
+}
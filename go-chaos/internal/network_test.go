@@ -13,3 +13,333 @@
 // limitations under the License.
 
 package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldBuildTrafficControlCommandForTargetIp(t *testing.T) {
+	// given
+	qdisc := "netem delay 100ms 10ms"
+
+	// when
+	cmd := buildTrafficControlCommand(qdisc, []string{"10.0.0.1"})
+
+	// then
+	assert.Contains(t, cmd, "(tc qdisc show dev eth0 | grep -q 'qdisc prio 1: root' || tc qdisc add dev eth0 root handle 1: prio bands 16)")
+	assert.Contains(t, cmd, "tc qdisc add dev eth0 parent 1:$minor handle ${minor}0: netem delay 100ms 10ms")
+	assert.Contains(t, cmd, "tc filter add dev eth0 parent 1: protocol ip prio $band u32 match ip dst 10.0.0.1/32 flowid 1:$minor")
+	assert.NotContains(t, cmd, "tc qdisc replace")
+}
+
+func Test_ShouldApplyTrafficControlFaultsOnDifferentBands(t *testing.T) {
+	// given
+	tc := newTrafficControlStub(t)
+
+	// when
+	firstOutput := tc.run(buildTrafficControlCommand("netem delay 100ms", []string{"10.0.0.1", "10.0.0.2"}))
+	secondOutput := tc.run(buildTrafficControlCommand("tbf rate 1mbit burst 32kbit latency 400ms", []string{}))
+
+	// then
+	firstBand, err := parseTrafficControlBand(firstOutput)
+	require.NoError(t, err)
+	assert.Equal(t, 4, firstBand)
+	secondBand, err := parseTrafficControlBand(secondOutput)
+	require.NoError(t, err)
+	assert.Equal(t, 5, secondBand)
+
+	assert.Equal(t, []string{
+		"tc qdisc add dev eth0 root handle 1: prio bands 16",
+		"tc qdisc add dev eth0 parent 1:4 handle 40: netem delay 100ms",
+		"tc filter add dev eth0 parent 1: protocol ip prio 4 u32 match ip dst 10.0.0.1/32 flowid 1:4",
+		"tc filter add dev eth0 parent 1: protocol ip prio 4 u32 match ip dst 10.0.0.2/32 flowid 1:4",
+		"tc qdisc add dev eth0 parent 1:5 handle 50: tbf rate 1mbit burst 32kbit latency 400ms",
+		"tc filter add dev eth0 parent 1: protocol ip prio 5 u32 match ip dst 0.0.0.0/0 flowid 1:5",
+	}, tc.changes())
+}
+
+func Test_ShouldUseHexClassIdsForTrafficControlBands(t *testing.T) {
+	// given
+	tc := newTrafficControlStub(t)
+	for band := firstTrafficControlBand; band < 10; band++ {
+		tc.run(buildTrafficControlCommand("netem delay 100ms", []string{"10.0.0.1"}))
+	}
+
+	// when
+	output := tc.run(buildTrafficControlCommand("netem loss 5%", []string{"10.0.0.2"}))
+
+	// then
+	band, err := parseTrafficControlBand(output)
+	require.NoError(t, err)
+	assert.Equal(t, 10, band)
+	assert.Contains(t, tc.changes(), "tc qdisc add dev eth0 parent 1:a handle a0: netem loss 5%")
+	assert.Contains(t, tc.changes(), "tc filter add dev eth0 parent 1: protocol ip prio 10 u32 match ip dst 10.0.0.2/32 flowid 1:a")
+}
+
+func Test_ShouldFailIfNoTrafficControlBandIsLeft(t *testing.T) {
+	// given
+	tc := newTrafficControlStub(t)
+	for band := firstTrafficControlBand; band <= lastTrafficControlBand; band++ {
+		tc.run(buildTrafficControlCommand("netem delay 100ms", []string{"10.0.0.1"}))
+	}
+
+	// when
+	output, err := tc.tryRun(buildTrafficControlCommand("netem delay 100ms", []string{"10.0.0.2"}))
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, output, "no free traffic control band left")
+}
+
+func Test_ShouldRemoveTrafficControlOfSingleBand(t *testing.T) {
+	// given
+	band := 10
+
+	// when
+	cmd := buildRemoveTrafficControlBandCommand(band)
+
+	// then
+	assert.Equal(t, "{ tc filter del dev eth0 parent 1: protocol ip prio 10; tc qdisc del dev eth0 parent 1:a; }", cmd)
+}
+
+func Test_ShouldFailToParseMissingTrafficControlBand(t *testing.T) {
+	// given
+	output := "Reading package lists..."
+
+	// when
+	_, err := parseTrafficControlBand(output)
+
+	// then
+	require.Error(t, err)
+}
+
+// trafficControlStub runs the traffic control commands with a tc stub, which records the added qdiscs and filters.
+// The added qdiscs are shown again, similar to tc, which allows to apply several faults one after the other.
+type trafficControlStub struct {
+	t   *testing.T
+	dir string
+}
+
+func newTrafficControlStub(t *testing.T) trafficControlStub {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("requires sh to run the traffic control commands")
+	}
+
+	dir := t.TempDir()
+	stub := `#!/bin/sh
+case "$1 $2" in
+"qdisc show")
+	cat "$TC_STATE" 2> /dev/null ;;
+"qdisc add")
+	echo "tc $*" >> "$TC_CHANGES"
+	if [ "$5" = "root" ]; then
+		echo "qdisc prio 1: root refcnt 2 bands 16" >> "$TC_STATE"
+	else
+		parent=$6; handle=$8; kind=$9; shift 9
+		echo "qdisc $kind $handle parent $parent $*" >> "$TC_STATE"
+	fi ;;
+*)
+	echo "tc $*" >> "$TC_CHANGES" ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tc"), []byte(stub), 0o755))
+
+	return trafficControlStub{t: t, dir: dir}
+}
+
+// Adds a stub for the given tool (e.g. ip), which records its invocations as changes and prints nothing
+func (s trafficControlStub) addRecordingStub(tool string) {
+	stub := "#!/bin/sh\necho \"" + tool + " $*\" >> \"$TC_CHANGES\"\n"
+	require.NoError(s.t, os.WriteFile(filepath.Join(s.dir, tool), []byte(stub), 0o755))
+}
+
+// Returns the tc commands, which changed the qdiscs or filters
+func (s trafficControlStub) changes() []string {
+	changes, err := os.ReadFile(filepath.Join(s.dir, "changes"))
+	require.NoError(s.t, err)
+	return strings.Split(strings.TrimSpace(string(changes)), "\n")
+}
+
+func (s trafficControlStub) tryRun(cmd string) (string, error) {
+	shell := exec.Command("sh", "-c", cmd)
+	shell.Env = append(os.Environ(),
+		"PATH="+s.dir+string(os.PathListSeparator)+os.Getenv("PATH"),
+		"TC_STATE="+filepath.Join(s.dir, "state"),
+		"TC_CHANGES="+filepath.Join(s.dir, "changes"))
+	output, err := shell.CombinedOutput()
+	return string(output), err
+}
+
+func (s trafficControlStub) run(cmd string) string {
+	output, err := s.tryRun(cmd)
+	require.NoError(s.t, err, output)
+	return output
+}
+
+func Test_ShouldRemoveAllNetworkFaultsWithOneCommand(t *testing.T) {
+	// given
+	tc := newTrafficControlStub(t)
+	tc.addRecordingStub("ip")
+	tc.addRecordingStub("iptables")
+	tc.run(buildTrafficControlCommand("netem delay 100ms", []string{}))
+
+	// when
+	tc.run(buildRemoveNetworkFaultsCommand([]string{"10.0.0.1", "10.0.0.2"}))
+
+	// then
+	assert.Equal(t, []string{
+		"tc qdisc add dev eth0 root handle 1: prio bands 16",
+		"tc qdisc add dev eth0 parent 1:4 handle 40: netem delay 100ms",
+		"tc filter add dev eth0 parent 1: protocol ip prio 4 u32 match ip dst 0.0.0.0/0 flowid 1:4",
+		"ip route del unreachable 10.0.0.1",
+		"ip route del unreachable 10.0.0.2",
+		"tc qdisc del dev eth0 root",
+		"iptables -t filter -S OUTPUT",
+		"iptables -t nat -S OUTPUT",
+	}, tc.changes())
+}
+
+func Test_ShouldNotRemoveDefaultRootQdisc(t *testing.T) {
+	// given
+	tc := newTrafficControlStub(t)
+	tc.addRecordingStub("ip")
+	tc.addRecordingStub("iptables")
+
+	// when
+	tc.run(buildRemoveNetworkFaultsCommand(nil))
+
+	// then
+	assert.Equal(t, []string{
+		"ip route show type unreachable",
+		"iptables -t filter -S OUTPUT",
+		"iptables -t nat -S OUTPUT",
+	}, tc.changes())
+}
+
+func Test_ShouldBuildBlockPortsCommand(t *testing.T) {
+	// given
+	ports := []int{26501, 26502}
+
+	// when
+	cmd := buildPortsCommand("-A", "10.0.0.1", ports)
+
+	// then
+	assert.Equal(t, "iptables -A OUTPUT -p tcp -d 10.0.0.1 -m multiport --dports 26501,26502 -m comment --comment zbchaos -j DROP && "+
+		"iptables -A OUTPUT -p tcp -d 10.0.0.1 -m multiport --sports 26501,26502 -m comment --comment zbchaos -j DROP", cmd)
+}
+
+func Test_ShouldHealAfterDuration(t *testing.T) {
+	// given
+	duration := 90 * time.Second
+
+	// when
+	cmd := healAfter("ip route del unreachable 10.0.0.1", duration)
+
+	// then
+	assert.Equal(t, "sleep 90 && ip route del unreachable 10.0.0.1", cmd)
+}
+
+func Test_ShouldMarkPendingNetworkHeal(t *testing.T) {
+	// given
+	duration := 90 * time.Second
+
+	// when
+	cmd := healNetworkFaultAfter("ip route del unreachable 10.0.0.1", duration)
+
+	// then
+	assert.Equal(t, ": zbchaos-network-heal; sleep 90 && ip route del unreachable 10.0.0.1", cmd)
+}
+
+func Test_ShouldStopPendingNetworkHeals(t *testing.T) {
+	// given
+	tc := newTrafficControlStub(t)
+	tc.addRecordingStub("ip")
+	pendingHeal := exec.Command("sh", "-c", healNetworkFaultAfter("ip route del unreachable 10.0.0.1", time.Minute))
+	pendingHeal.Env = append(os.Environ(), "PATH="+tc.dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	require.NoError(t, pendingHeal.Start())
+	exited := make(chan error, 1)
+	go func() { exited <- pendingHeal.Wait() }()
+
+	// when
+	tc.run(buildStopPendingNetworkHealsCommand())
+
+	// then
+	select {
+	case err := <-exited:
+		assert.Error(t, err, "expected the pending heal to be killed")
+	case <-time.After(10 * time.Second):
+		_ = pendingHeal.Process.Kill()
+		t.Fatal("expected the pending heal to be stopped")
+	}
+	_, err := os.Stat(filepath.Join(tc.dir, "changes"))
+	assert.True(t, os.IsNotExist(err), "expected the heal command not to run")
+}
+
+func Test_ShouldBuildRemoveIptablesRulesCommandForZbchaosRulesOnly(t *testing.T) {
+	// given
+
+	// when
+	cmd := buildRemoveIptablesRulesCommand()
+
+	// then
+	assert.Equal(t, "iptables -t filter -S OUTPUT | grep -- '--comment zbchaos' | sed 's/^-A/-D/' | while read -r rule; do eval \"iptables -t filter $rule\"; done && "+
+		"iptables -t nat -S OUTPUT | grep -- '--comment zbchaos' | sed 's/^-A/-D/' | while read -r rule; do eval \"iptables -t nat $rule\"; done", cmd)
+}
+
+func Test_ShouldResolveContainerName(t *testing.T) {
+	assert.Equal(t, "zeebe-gateway", getZeebeContainerName("zeebe-gateway-6f7b8c9d-abcde"))
+	assert.Equal(t, "zeebe", getZeebeContainerName("zeebe-0"))
+}
+
+func Test_ShouldBuildNetemArgumentsForDelay(t *testing.T) {
+	// given
+	cfg := NetemCfg{Delay: "100ms", Jitter: "10ms"}
+
+	// when
+	args := cfg.String()
+
+	// then
+	assert.Equal(t, "netem delay 100ms 10ms", args)
+}
+
+func Test_ShouldBuildNetemArgumentsForPercentages(t *testing.T) {
+	// given
+	cfg := NetemCfg{Delay: "10ms", Loss: 5, Duplicate: 1, Reorder: 25, Corrupt: 0.1}
+
+	// when
+	args := cfg.String()
+
+	// then
+	assert.Equal(t, "netem delay 10ms loss 5% duplicate 1% reorder 25% corrupt 0.1%", args)
+}
+
+func Test_ShouldRejectReorderWithoutDelay(t *testing.T) {
+	// given
+	cfg := NetemCfg{Reorder: 25}
+
+	// when
+	err := cfg.validate()
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires a delay")
+}
+
+func Test_ShouldRejectInvalidPercentage(t *testing.T) {
+	// given
+	cfg := NetemCfg{Loss: 101}
+
+	// when
+	err := cfg.validate()
+
+	// then
+	require.Error(t, err)
+}
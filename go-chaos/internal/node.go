@@ -0,0 +1,182 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Marks the nodes which have been cordoned by zbchaos, such that only these nodes are uncordoned again
+const drainedNodeAnnotation = "zbchaos.camunda.io/drained"
+
+// How long an eviction is retried on drain, while it is blocked by a PodDisruptionBudget
+const evictionRetryTimeout = 5 * time.Minute
+
+// ErrEvictionBlocked is returned if an eviction is rejected, since it would violate a PodDisruptionBudget
+var ErrEvictionBlocked = errors.New("eviction is blocked by a PodDisruptionBudget")
+
+// Evicts the given pod via the Eviction API, which respects the PodDisruptionBudgets of the pod.
+// Returns an error wrapping ErrEvictionBlocked, if a PodDisruptionBudget doesn't allow the eviction.
+func (c K8Client) EvictPod(podName string) error {
+	return c.EvictPodWithGracePeriod(podName, nil)
+}
+
+// Evicts the given pod like EvictPod, the grace period (in seconds) can be nil to use the default of the pod.
+func (c K8Client) EvictPodWithGracePeriod(podName string, gracePeriodSec *int64) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: podName, Namespace: c.GetCurrentNamespace()},
+		DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: gracePeriodSec},
+	}
+	err := c.Clientset.PolicyV1().Evictions(c.GetCurrentNamespace()).Evict(context.TODO(), eviction)
+	if k8sErrors.IsTooManyRequests(err) {
+		return fmt.Errorf("failed to evict pod %s, %w: %v", podName, ErrEvictionBlocked, err)
+	}
+	return err
+}
+
+// Evicts the given pod, retries as long as the eviction is blocked by a PodDisruptionBudget until the timeout.
+func (c K8Client) evictPodWithRetry(podName string, timeout time.Duration, retryInterval time.Duration) error {
+	timedOut := time.After(timeout)
+	for {
+		err := c.EvictPod(podName)
+		if !errors.Is(err, ErrEvictionBlocked) {
+			return err
+		}
+		LogInfo("Eviction of pod %s is blocked by a PodDisruptionBudget. Will retry.", podName)
+
+		select {
+		case <-timedOut:
+			return fmt.Errorf("pod %s could not be evicted with in given timeout %v: %w", podName, timeout, err)
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// Returns the name of the k8s node, on which the given pod is scheduled.
+func (c K8Client) GetNodeOfPod(podName string) (string, error) {
+	pod, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if pod.Spec.NodeName == "" {
+		return "", fmt.Errorf("expected pod %s to be scheduled on a node, but it is not scheduled", podName)
+	}
+	return pod.Spec.NodeName, nil
+}
+
+// Cordons the given node and evicts the pods of the current namespace, which are running on it.
+// Like kubectl drain, pods of DaemonSets and mirror pods are not evicted. Returns the names of the evicted pods.
+func (c K8Client) DrainNode(nodeName string) ([]string, error) {
+	err := c.setNodeCordoned(nodeName, true)
+	if err != nil {
+		return nil, err
+	}
+	LogInfo("Cordoned node %s", nodeName)
+
+	pods, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var evictedPods []string
+	for _, pod := range pods.Items {
+		if !isEvictable(pod, nodeName) {
+			continue
+		}
+
+		err = c.evictPodWithRetry(pod.Name, evictionRetryTimeout, 5*time.Second)
+		if err != nil {
+			return evictedPods, err
+		}
+		LogInfo("Evicted pod %s", pod.Name)
+		evictedPods = append(evictedPods, pod.Name)
+	}
+	return evictedPods, nil
+}
+
+func isEvictable(pod v1.Pod, nodeName string) bool {
+	if pod.Spec.NodeName != nodeName || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return false
+	}
+	if _, isMirrorPod := pod.Annotations[v1.MirrorPodAnnotationKey]; isMirrorPod {
+		return false
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
+
+// Uncordons all nodes, which have been cordoned by DrainNode. Returns the names of the uncordoned nodes.
+func (c K8Client) UncordonDrainedNodes() ([]string, error) {
+	nodes, err := c.Clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var uncordonedNodes []string
+	for _, node := range nodes.Items {
+		if _, drained := node.Annotations[drainedNodeAnnotation]; !drained {
+			continue
+		}
+
+		err = c.setNodeCordoned(node.Name, false)
+		if err != nil {
+			return uncordonedNodes, err
+		}
+		uncordonedNodes = append(uncordonedNodes, node.Name)
+	}
+	return uncordonedNodes, nil
+}
+
+// Marks the node as (un)schedulable, cordoned nodes are annotated to recognize them on uncordon.
+// Nodes which are already cordoned are not annotated, such that they stay cordoned on uncordon.
+func (c K8Client) setNodeCordoned(nodeName string, cordoned bool) error {
+	node, err := c.Clientset.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if cordoned && node.Spec.Unschedulable {
+		// the node has been cordoned before (e.g. by an operator), which means we must not uncordon it later
+		LogInfo("Node %s is already cordoned, it will not be uncordoned by zbchaos", nodeName)
+		return nil
+	}
+
+	node.Spec.Unschedulable = cordoned
+	if cordoned {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[drainedNodeAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	} else {
+		delete(node.Annotations, drainedNodeAnnotation)
+	}
+
+	_, err = c.Clientset.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+	return err
+}
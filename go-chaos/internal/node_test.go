@@ -0,0 +1,158 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8testing "k8s.io/client-go/testing"
+)
+
+func Test_ShouldCordonAndUncordonNode(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	_, err := k8Client.Clientset.CoreV1().Nodes().Create(context.TODO(), &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = k8Client.Clientset.CoreV1().Nodes().Create(context.TODO(), &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// when
+	_, err = k8Client.DrainNode("node-1")
+	require.NoError(t, err)
+	cordonedNode, err := k8Client.Clientset.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	uncordonedNodes, err := k8Client.UncordonDrainedNodes()
+	require.NoError(t, err)
+
+	// then
+	assert.True(t, cordonedNode.Spec.Unschedulable)
+	assert.Contains(t, cordonedNode.Annotations, drainedNodeAnnotation)
+	assert.Equal(t, []string{"node-1"}, uncordonedNodes)
+	node, err := k8Client.Clientset.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.False(t, node.Spec.Unschedulable)
+	assert.NotContains(t, node.Annotations, drainedNodeAnnotation)
+}
+
+func Test_ShouldNotUncordonAlreadyCordonedNode(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	_, err := k8Client.Clientset.CoreV1().Nodes().Create(context.TODO(), &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       v1.NodeSpec{Unschedulable: true},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// when
+	_, err = k8Client.DrainNode("node-1")
+	require.NoError(t, err)
+	uncordonedNodes, err := k8Client.UncordonDrainedNodes()
+	require.NoError(t, err)
+
+	// then
+	assert.Empty(t, uncordonedNodes)
+	node, err := k8Client.Clientset.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, node.Spec.Unschedulable)
+	assert.NotContains(t, node.Annotations, drainedNodeAnnotation)
+}
+
+func Test_ShouldEvictPod(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	var evictedPod string
+	k8Client.Clientset.(*fake.Clientset).PrependReactor("create", "pods", func(action k8testing.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		evictedPod = action.(k8testing.CreateAction).GetObject().(*policyv1.Eviction).Name
+		return true, nil, nil
+	})
+
+	// when
+	err := k8Client.EvictPod("zeebe-0")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "zeebe-0", evictedPod)
+}
+
+func Test_ShouldReportEvictionBlockedByPodDisruptionBudget(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	k8Client.Clientset.(*fake.Clientset).PrependReactor("create", "pods", func(action k8testing.Action) (bool, runtime.Object, error) {
+		return true, nil, k8sErrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+	})
+
+	// when
+	err := k8Client.EvictPod("zeebe-0")
+	retryErr := k8Client.evictPodWithRetry("zeebe-0", 10*time.Millisecond, time.Millisecond)
+
+	// then
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrEvictionBlocked))
+	assert.Contains(t, err.Error(), "zeebe-0")
+	assert.True(t, errors.Is(retryErr, ErrEvictionBlocked))
+}
+
+func Test_ShouldSkipDaemonSetAndMirrorPodsOnDrain(t *testing.T) {
+	// given
+	nodeName := "node-1"
+	daemonSetPod := v1.Pod{Spec: v1.PodSpec{NodeName: nodeName},
+		ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet"}}}}
+	mirrorPod := v1.Pod{Spec: v1.PodSpec{NodeName: nodeName},
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1.MirrorPodAnnotationKey: "true"}}}
+	brokerPod := v1.Pod{Spec: v1.PodSpec{NodeName: nodeName},
+		ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet"}}}}
+	otherNodePod := v1.Pod{Spec: v1.PodSpec{NodeName: "node-2"}}
+
+	// when
+
+	// then
+	assert.False(t, isEvictable(daemonSetPod, nodeName))
+	assert.False(t, isEvictable(mirrorPod, nodeName))
+	assert.True(t, isEvictable(brokerPod, nodeName))
+	assert.False(t, isEvictable(otherNodePod, nodeName))
+}
+
+func Test_ShouldEvictPodWithGracePeriod(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	var eviction *policyv1.Eviction
+	k8Client.Clientset.(*fake.Clientset).PrependReactor("create", "pods", func(action k8testing.Action) (bool, runtime.Object, error) {
+		eviction = action.(k8testing.CreateAction).GetObject().(*policyv1.Eviction)
+		return true, nil, nil
+	})
+	gracePeriodSec := int64(0)
+
+	// when
+	err := k8Client.EvictPodWithGracePeriod("zeebe-1", &gracePeriodSec)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "zeebe-1", eviction.Name)
+	assert.Equal(t, int64(0), *eviction.DeleteOptions.GracePeriodSeconds)
+}
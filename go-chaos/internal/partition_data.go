@@ -0,0 +1,114 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// The targets of a data corruption
+const (
+	// the largest file of the latest snapshot
+	CorruptionTargetSnapshot = "snapshot"
+	// the last sealed journal segment, the newest segment is preallocated and mostly not written yet
+	CorruptionTargetJournal = "journal"
+)
+
+// The modes of a data corruption
+const (
+	// random bytes are written into the middle of the file
+	CorruptionModeFlip = "flip"
+	// the file is truncated to half of its size
+	CorruptionModeTruncate = "truncate"
+)
+
+// How many bytes are overwritten with random bytes, when flipping bytes
+const corruptionBytes = 64
+
+// Returns the data directory of the given partition
+func partitionDirectory(partitionId int) string {
+	return fmt.Sprintf("%s/raft-partition/partitions/%d", zeebeDataDirectory, partitionId)
+}
+
+// Deletes the data directory of the given partition on the given broker pod. Fails if the broker has no data for the
+// partition.
+func DeletePartitionDataOfPod(k8Client K8Client, podName string, partitionId int) error {
+	return k8Client.ExecuteCmdOnPod([]string{"sh", "-c", buildDeletePartitionDataCommand(partitionId)}, podName)
+}
+
+func buildDeletePartitionDataCommand(partitionId int) string {
+	directory := partitionDirectory(partitionId)
+	return fmt.Sprintf(`[ -d %s ] || { echo "no data found for partition %d"; exit 1; }; rm -rf %s`, directory, partitionId, directory)
+}
+
+// Corrupts the data of the given partition on the given broker pod, either the latest snapshot or the last sealed
+// journal segment. Returns the path of the corrupted file.
+func CorruptPartitionDataOfPod(k8Client K8Client, podName string, partitionId int, target string, mode string) (string, error) {
+	cmd, err := buildCorruptionCommand(partitionId, target, mode)
+	if err != nil {
+		return "", err
+	}
+
+	output := bytes.Buffer{}
+	err = k8Client.ExecuteCmdOnPodWriteIntoOutput([]string{"sh", "-c", cmd}, podName, &output)
+	if err != nil {
+		return "", err
+	}
+
+	// the corrupted file is printed as last line
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// Builds the command to corrupt the target file of the given partition, which prints the corrupted file at the end.
+func buildCorruptionCommand(partitionId int, target string, mode string) (string, error) {
+	directory := partitionDirectory(partitionId)
+
+	var findFileCmd, notFoundMsg string
+	switch target {
+	case CorruptionTargetSnapshot:
+		// the largest file of the latest snapshot, the snapshots are directories next to their checksum files
+		findFileCmd = fmt.Sprintf(`snapshot=$(ls -td %s/snapshots/*/ 2>/dev/null | head -1); file=$([ -n "$snapshot" ] && find "$snapshot" -maxdepth 1 -type f -printf '%%s %%p\n' | sort -nr | head -1 | cut -d' ' -f2)`,
+			directory)
+		notFoundMsg = fmt.Sprintf("no snapshot file found for partition %d", partitionId)
+	case CorruptionTargetJournal:
+		// the segment before the one with the highest index, the segments are preallocated such that the middle of the
+		// newest segment is usually not written yet, while a sealed segment is written up to its end
+		findFileCmd = fmt.Sprintf(`file=$(ls -v %s/raft-partition-partition-%d-*.log 2>/dev/null | head -n -1 | tail -1)`, directory, partitionId)
+		notFoundMsg = fmt.Sprintf("no sealed journal segment found for partition %d, wait until the first segment is full", partitionId)
+	default:
+		return "", fmt.Errorf("expected corruption target to be one of [%s, %s], but got '%s'", CorruptionTargetSnapshot, CorruptionTargetJournal, target)
+	}
+
+	var corruptCmd string
+	switch mode {
+	case CorruptionModeFlip:
+		corruptCmd = fmt.Sprintf(`dd if=/dev/urandom of="$file" bs=1 count=%d seek=$((size / 2)) conv=notrunc 2>/dev/null`, corruptionBytes)
+	case CorruptionModeTruncate:
+		corruptCmd = `truncate -s $((size / 2)) "$file"`
+	default:
+		return "", fmt.Errorf("expected corruption mode to be one of [%s, %s], but got '%s'", CorruptionModeFlip, CorruptionModeTruncate, mode)
+	}
+
+	return strings.Join([]string{
+		findFileCmd,
+		fmt.Sprintf(`[ -n "$file" ] || { echo "%s"; exit 1; }`, notFoundMsg),
+		`size=$(stat -c %s "$file")`,
+		corruptCmd,
+		`echo "$file"`,
+	}, "; "), nil
+}
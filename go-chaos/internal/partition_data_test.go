@@ -0,0 +1,126 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldBuildJournalCorruptionCommand(t *testing.T) {
+	// given
+	partitionId := 3
+
+	// when
+	cmd, err := buildCorruptionCommand(partitionId, CorruptionTargetJournal, CorruptionModeTruncate)
+
+	// then
+	require.NoError(t, err)
+	assert.Contains(t, cmd, "ls -v /usr/local/zeebe/data/raft-partition/partitions/3/raft-partition-partition-3-*.log 2>/dev/null | head -n -1 | tail -1")
+	assert.Contains(t, cmd, `truncate -s $((size / 2)) "$file"`)
+	assert.Contains(t, cmd, `echo "no sealed journal segment found for partition 3, wait until the first segment is full"`)
+}
+
+func Test_ShouldCorruptLastSealedJournalSegment(t *testing.T) {
+	// given
+	dataDir := t.TempDir()
+	partitionDir := dataDir + "/raft-partition/partitions/1"
+	require.NoError(t, os.MkdirAll(partitionDir, 0755))
+	written := bytes.Repeat([]byte("a"), 1024)
+	for _, segment := range []string{"raft-partition-partition-1-1.log", "raft-partition-partition-1-2.log"} {
+		require.NoError(t, os.WriteFile(partitionDir+"/"+segment, written, 0644))
+	}
+	// the newest segment is preallocated, it is only written at the beginning
+	require.NoError(t, os.WriteFile(partitionDir+"/raft-partition-partition-1-10.log", make([]byte, 1024), 0644))
+	cmd, err := buildCorruptionCommand(1, CorruptionTargetJournal, CorruptionModeTruncate)
+	require.NoError(t, err)
+
+	// when
+	output, err := exec.Command("sh", "-c", strings.ReplaceAll(cmd, zeebeDataDirectory, dataDir)).CombinedOutput()
+
+	// then
+	require.NoError(t, err, string(output))
+	assert.Equal(t, partitionDir+"/raft-partition-partition-1-2.log", strings.TrimSpace(string(output)))
+	corrupted, err := os.ReadFile(partitionDir + "/raft-partition-partition-1-2.log")
+	require.NoError(t, err)
+	assert.Len(t, corrupted, 512)
+	newest, err := os.ReadFile(partitionDir + "/raft-partition-partition-1-10.log")
+	require.NoError(t, err)
+	assert.Len(t, newest, 1024)
+}
+
+func Test_ShouldNotCorruptNewestJournalSegment(t *testing.T) {
+	// given
+	dataDir := t.TempDir()
+	partitionDir := dataDir + "/raft-partition/partitions/1"
+	require.NoError(t, os.MkdirAll(partitionDir, 0755))
+	require.NoError(t, os.WriteFile(partitionDir+"/raft-partition-partition-1-1.log", make([]byte, 1024), 0644))
+	cmd, err := buildCorruptionCommand(1, CorruptionTargetJournal, CorruptionModeFlip)
+	require.NoError(t, err)
+
+	// when
+	output, err := exec.Command("sh", "-c", strings.ReplaceAll(cmd, zeebeDataDirectory, dataDir)).CombinedOutput()
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, string(output), "no sealed journal segment found for partition 1")
+	segment, err := os.ReadFile(partitionDir + "/raft-partition-partition-1-1.log")
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, 1024), segment)
+}
+
+func Test_ShouldBuildSnapshotCorruptionCommand(t *testing.T) {
+	// given
+	partitionId := 1
+
+	// when
+	cmd, err := buildCorruptionCommand(partitionId, CorruptionTargetSnapshot, CorruptionModeFlip)
+
+	// then
+	require.NoError(t, err)
+	assert.Contains(t, cmd, "ls -td /usr/local/zeebe/data/raft-partition/partitions/1/snapshots/*/")
+	assert.Contains(t, cmd, "-printf '%s %p\\n' | sort -nr")
+	assert.Contains(t, cmd, `dd if=/dev/urandom of="$file" bs=1 count=64 seek=$((size / 2)) conv=notrunc`)
+}
+
+func Test_ShouldRejectUnknownCorruptionTargetAndMode(t *testing.T) {
+	// given
+	partitionId := 1
+
+	// when
+	_, targetErr := buildCorruptionCommand(partitionId, "exporter", CorruptionModeFlip)
+	_, modeErr := buildCorruptionCommand(partitionId, CorruptionTargetJournal, "zero")
+
+	// then
+	require.Error(t, targetErr)
+	require.Error(t, modeErr)
+}
+
+func Test_ShouldBuildDeletePartitionDataCommand(t *testing.T) {
+	// given
+	partitionId := 2
+
+	// when
+	cmd := buildDeletePartitionDataCommand(partitionId)
+
+	// then
+	assert.Equal(t, `[ -d /usr/local/zeebe/data/raft-partition/partitions/2 ] || { echo "no data found for partition 2"; exit 1; }; rm -rf /usr/local/zeebe/data/raft-partition/partitions/2`, cmd)
+}
@@ -15,6 +15,7 @@
 package internal
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
@@ -29,6 +30,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/portforward"
@@ -256,6 +258,66 @@ func (c K8Client) AwaitPodReadiness(podName string, timeout time.Duration) error
 	}
 }
 
+// Waits until the pod with the given name has been recreated, i.e. has a different UID than before, and is ready.
+func (c K8Client) AwaitPodRecreation(podName string, previousUid types.UID, timeout time.Duration) error {
+	timedOut := time.After(timeout)
+	ticker := time.Tick(1 * time.Second)
+
+	// Keep checking until we're timed out
+	for {
+		select {
+		case <-timedOut:
+			return fmt.Errorf("Pod %s has not been recreated and ready with in given timeout %v", podName, timeout)
+		case <-ticker:
+			pod, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).Get(context.TODO(), podName, metav1.GetOptions{})
+			if err != nil {
+				LogVerbose("Failed to get pod %s. Will retry", podName)
+			} else if pod.UID == previousUid {
+				LogVerbose("Pod %s has not been recreated yet. Wait for some seconds", podName)
+			} else if pod.Status.Phase == v1.PodRunning && len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].Ready {
+				return nil
+			} else {
+				LogVerbose("Pod %s is in phase %s, but not ready. Wait for some seconds", podName, pod.Status.Phase)
+			}
+		}
+	}
+}
+
+// Returns the sum of the restart counts of all containers of the given pod.
+func (c K8Client) getContainerRestartCount(podName string) (int32, error) {
+	pod, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	restartCount := int32(0)
+	for _, status := range pod.Status.ContainerStatuses {
+		restartCount += status.RestartCount
+	}
+	return restartCount, nil
+}
+
+// Waits until a container of the given pod has been restarted, i.e. the restart count is higher than the given one.
+func (c K8Client) awaitContainerRestart(podName string, previousRestartCount int32, timeout time.Duration) error {
+	timedOut := time.After(timeout)
+	ticker := time.Tick(1 * time.Second)
+
+	// Keep checking until we're timed out
+	for {
+		select {
+		case <-timedOut:
+			return fmt.Errorf("container of pod %s has not been restarted within given timeout %v", podName, timeout)
+		case <-ticker:
+			restartCount, err := c.getContainerRestartCount(podName)
+			if err != nil {
+				LogVerbose("Failed to get pod %s. Will retry", podName)
+			} else if restartCount > previousRestartCount {
+				return nil
+			}
+		}
+	}
+}
+
 func (c K8Client) RestartPod(podName string) error {
 	return c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).Delete(context.TODO(), podName, metav1.DeleteOptions{})
 }
@@ -416,11 +478,199 @@ func (c K8Client) createPortForwardUrlForPod(podName string) *url.URL {
 		URL()
 }
 
+// The image built from debug/Dockerfile, which contains all tools to inject faults. It is tagged with each release.
+const ToolsDebugImageRepository = "gcr.io/zeebe-io/zbchaos-debug"
+
+// The image which can be used as fallback for the debug containers, e.g. if the tools image can't be pulled.
+// It doesn't contain the tools to inject faults, which means they are installed on each invocation.
+const FallbackDebugImage = "camunda/zeebe"
+
+// The image which is used for all debug containers
+var DebugImage = ToolsDebugImage("")
+
+// Whether the debug image contains all tools to inject faults, even if it is not the tools image (e.g. a mirror of it).
+var DebugImageHasTools = false
+
+// Returns the tools image for the given zbchaos version, such that the tools match the commands of this version.
+// Versions which are not released (e.g. development builds) use the latest tools image.
+func ToolsDebugImage(version string) string {
+	if version == "" || version == "development" {
+		return ToolsDebugImageRepository + ":latest"
+	}
+	return ToolsDebugImageRepository + ":" + version
+}
+
+// Returns whether the debug image contains the tools already, which is the case for the tools image of any version.
+func debugImageHasTools() bool {
+	return DebugImageHasTools || imageRepository(DebugImage) == ToolsDebugImageRepository
+}
+
+// Returns the repository of the given image reference, without its tag or digest.
+func imageRepository(image string) string {
+	repository, _, _ := strings.Cut(image, "@")
+	if tagIndex := strings.LastIndex(repository, ":"); tagIndex > strings.LastIndex(repository, "/") {
+		repository = repository[:tagIndex]
+	}
+	return repository
+}
+
+// Returns the command to install the given packages in the debug container, which is only necessary if
+// the debug image doesn't contain the tools already. Returns an empty string otherwise.
+func toolSetup(packages ...string) string {
+	if debugImageHasTools() {
+		return ""
+	}
+	return "apt update && apt install -y " + strings.Join(packages, " ")
+}
+
+// Returns the given command prefixed with the installation of the given packages, if necessary.
+func withToolSetup(cmd string, packages ...string) string {
+	return joinCommands(toolSetup(packages...), cmd)
+}
+
+// Joins the non-empty commands, such that each command is only executed if the previous one succeeded.
+func joinCommands(cmds ...string) string {
+	var nonEmptyCmds []string
+	for _, cmd := range cmds {
+		if cmd != "" {
+			nonEmptyCmds = append(nonEmptyCmds, cmd)
+		}
+	}
+	return strings.Join(nonEmptyCmds, " && ")
+}
+
+// How long we wait for a debug container to complete its command, or to start a long-running command.
+// This includes pulling the image and installing the tools.
+const debugContainerTimeout = 5 * time.Minute
+
+// The marker which is printed by a long-running debug container command, after its setup succeeded
+const debugContainerStartedMarker = "zbchaos: command started"
+
+// Executes the given command in a new debug container of the pod and waits until the container is terminated.
+// Returns an error, containing the exit code and output, if the command fails.
 func (c K8Client) ExecuteCommandViaDebugContainer(podName string, containerName string, debugImage string, cmd []string) error {
-	pod, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).Get(context.TODO(), podName, metav1.GetOptions{})
+	_, err := c.ExecuteCommandViaDebugContainerWithOutput(podName, containerName, debugImage, cmd, debugContainerTimeout)
+	return err
+}
+
+// Starts the given long-running command (e.g. stress) in a new debug container of the pod, after the (optional) setup
+// command succeeded. We only wait until the setup is done and the command is started, which means failures of the setup
+// (e.g. failing tool installation) are reported, while the command itself keeps running in the background.
+func (c K8Client) StartCommandViaDebugContainer(podName string, containerName string, debugImage string, setupCmd string, cmd string) error {
+	shellCmd := joinCommands(setupCmd, fmt.Sprintf("echo '%s'", debugContainerStartedMarker), cmd)
+	name, err := c.addDebugContainer(podName, containerName, debugImage, []string{"sh", "-c", shellCmd})
 	if err != nil {
 		return err
 	}
+	return c.awaitDebugContainerStarted(podName, name, debugContainerTimeout)
+}
+
+// Executes the given command in a new debug container of the pod and waits until the container is terminated.
+// Returns the logs of the debug container, which allows to retrieve the output of the command. The logs are followed
+// while waiting, such that the progress of long-running commands (e.g. installing tools) is visible in verbose mode.
+// Returns an error if the command fails or doesn't complete within the given timeout.
+func (c K8Client) ExecuteCommandViaDebugContainerWithOutput(podName string, containerName string, debugImage string, cmd []string, timeout time.Duration) (string, error) {
+	name, err := c.addDebugContainer(podName, containerName, debugImage, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	followed := make(chan debugContainerLogs, 1)
+	go func() {
+		logs, err := c.followContainerLogs(ctx, podName, name)
+		followed <- debugContainerLogs{logs: logs, err: err}
+	}()
+
+	terminated, err := c.awaitDebugContainerTermination(podName, name, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	// the followed logs end with the container, if following failed we fetch the logs again
+	var logs string
+	select {
+	case result := <-followed:
+		logs, err = result.logs, result.err
+	case <-time.After(followLogsTimeout):
+		err = fmt.Errorf("logs of debug container %s on pod %s did not end within %v", name, podName, followLogsTimeout)
+	}
+	if err != nil {
+		LogVerbose("Failed to follow logs of debug container %s, fetch them again. Error: %s", name, err.Error())
+		logs, err = c.getContainerLogs(podName, name)
+		if err != nil {
+			return "", err
+		}
+	}
+	LogVerbose("Debug container %s terminated with exit code %d", name, terminated.ExitCode)
+
+	if terminated.ExitCode != 0 {
+		return logs, fmt.Errorf("debug container %s on pod %s failed with exit code %d: %s", name, podName, terminated.ExitCode, logs)
+	}
+	return logs, nil
+}
+
+// How long we wait for the end of the followed logs, after the debug container is terminated
+const followLogsTimeout = 10 * time.Second
+
+type debugContainerLogs struct {
+	logs string
+	err  error
+}
+
+// Follows the logs of the given container until it is terminated, each line is logged in verbose mode. Since the logs
+// can only be followed after the container is started, we retry until then or until the context is cancelled.
+func (c K8Client) followContainerLogs(ctx context.Context, podName string, containerName string) (string, error) {
+	logOptions := &v1.PodLogOptions{Container: containerName, Follow: true}
+	for {
+		stream, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).GetLogs(podName, logOptions).Stream(ctx)
+		if err == nil {
+			defer stream.Close()
+			return readLogs(stream, containerName)
+		}
+		LogVerbose("Logs of container %s on pod %s are not available yet. Will retry", containerName, podName)
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+}
+
+// Reads the logs until the end of the stream, each line is logged in verbose mode.
+func readLogs(stream io.Reader, containerName string) (string, error) {
+	logs := strings.Builder{}
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		logs.WriteString(line)
+		if line != "" {
+			LogVerbose("[%s] %s", containerName, strings.TrimSuffix(line, "\n"))
+		}
+		if err == io.EOF {
+			return logs.String(), nil
+		}
+		if err != nil {
+			return logs.String(), err
+		}
+	}
+}
+
+func (c K8Client) getContainerLogs(podName string, containerName string) (string, error) {
+	logs, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).GetLogs(podName, &v1.PodLogOptions{Container: containerName}).Do(context.TODO()).Raw()
+	if err != nil {
+		return "", err
+	}
+	return string(logs), nil
+}
+
+func (c K8Client) addDebugContainer(podName string, containerName string, debugImage string, cmd []string) (string, error) {
+	pod, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
 	name := "debug-" + rand.String(6)
 	debugContainer := v1.EphemeralContainer{
 		EphemeralContainerCommon: v1.EphemeralContainerCommon{
@@ -439,10 +689,79 @@ func (c K8Client) ExecuteCommandViaDebugContainer(podName string, containerName
 	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, debugContainer)
 	_, err = c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).UpdateEphemeralContainers(context.TODO(), pod.Name, pod, metav1.UpdateOptions{})
 	if err != nil {
-		return err
+		return "", err
 	}
 	LogVerbose("Debug container %s is running command %v", name, cmd)
-	return nil
+	return name, nil
+}
+
+// Waits until the debug container printed the started marker, or terminated (e.g. because the setup failed).
+func (c K8Client) awaitDebugContainerStarted(podName string, debugContainerName string, timeout time.Duration) error {
+	timedOut := time.After(timeout)
+	ticker := time.Tick(1 * time.Second)
+
+	// Keep checking until we're timed out
+	for {
+		select {
+		case <-timedOut:
+			return fmt.Errorf("debug container %s on pod %s has not started its command within given timeout %v", debugContainerName, podName, timeout)
+		case <-ticker:
+			pod, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).Get(context.TODO(), podName, metav1.GetOptions{})
+			if err != nil {
+				LogVerbose("Failed to get pod %s. Will retry", podName)
+				continue
+			}
+
+			for _, status := range pod.Status.EphemeralContainerStatuses {
+				if status.Name != debugContainerName || (status.State.Running == nil && status.State.Terminated == nil) {
+					continue
+				}
+
+				// the logs are only available after the container has been started
+				logs, err := c.getContainerLogs(podName, debugContainerName)
+				if err != nil {
+					LogVerbose("Failed to get logs of debug container %s. Will retry", debugContainerName)
+					continue
+				}
+
+				terminated := status.State.Terminated
+				if terminated != nil && terminated.ExitCode != 0 {
+					return fmt.Errorf("debug container %s on pod %s failed with exit code %d: %s", debugContainerName, podName, terminated.ExitCode, logs)
+				}
+				if terminated != nil || strings.Contains(logs, debugContainerStartedMarker) {
+					LogVerbose("Debug container %s started its command, output:\n%s", debugContainerName, logs)
+					return nil
+				}
+			}
+			LogVerbose("Debug container %s on pod %s has not started its command yet. Wait for some seconds", debugContainerName, podName)
+		}
+	}
+}
+
+func (c K8Client) awaitDebugContainerTermination(podName string, debugContainerName string, timeout time.Duration) (*v1.ContainerStateTerminated, error) {
+	timedOut := time.After(timeout)
+	ticker := time.Tick(1 * time.Second)
+
+	// Keep checking until we're timed out
+	for {
+		select {
+		case <-timedOut:
+			return nil, fmt.Errorf("debug container %s on pod %s is not terminated within given timeout %v", debugContainerName, podName, timeout)
+		case <-ticker:
+			pod, err := c.Clientset.CoreV1().Pods(c.GetCurrentNamespace()).Get(context.TODO(), podName, metav1.GetOptions{})
+			if err != nil {
+				LogVerbose("Failed to get pod %s. Will retry", podName)
+				continue
+			}
+
+			for _, status := range pod.Status.EphemeralContainerStatuses {
+				if status.Name == debugContainerName && status.State.Terminated != nil {
+					return status.State.Terminated, nil
+				}
+			}
+			LogVerbose("Debug container %s on pod %s is not terminated yet. Wait for some seconds", debugContainerName, podName)
+		}
+	}
 }
 
 func (c K8Client) ExecuteCmdOnPod(cmd []string, pod string) error {
@@ -15,6 +15,8 @@
 package internal
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -420,3 +422,171 @@ func Test_MustGatewayPortForward_ServiceSelection_NoServicePanics(t *testing.T)
 		k8Client.mustResolveGatewayServiceTarget(26500)
 	}, "expected panic when no gateway service exists")
 }
+
+func Test_ShouldAwaitDebugContainerTermination(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	_, err := k8Client.Clientset.CoreV1().Pods(k8Client.GetCurrentNamespace()).Create(context.TODO(), &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeebe-0"},
+		Status: v1.PodStatus{
+			EphemeralContainerStatuses: []v1.ContainerStatus{
+				{Name: "debug-running", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+				{Name: "debug-done", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1}}},
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// when
+	terminated, err := k8Client.awaitDebugContainerTermination("zeebe-0", "debug-done", 5*time.Second)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), terminated.ExitCode)
+}
+
+func Test_ShouldFollowDebugContainerLogs(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+
+	// when
+	logs, err := k8Client.followContainerLogs(context.TODO(), "zeebe-0", "debug-done")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "fake logs", logs)
+}
+
+func Test_ShouldReadAllLogLines(t *testing.T) {
+	// given
+	stream := strings.NewReader("fetch https://dl-cdn.alpinelinux.org/alpine\nOK: 10 MiB in 20 packages\nzbchaos: traffic control band 4")
+
+	// when
+	logs, err := readLogs(stream, "debug-install")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "fetch https://dl-cdn.alpinelinux.org/alpine\nOK: 10 MiB in 20 packages\nzbchaos: traffic control band 4", logs)
+}
+
+func Test_ShouldTimeoutOnRunningDebugContainer(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	_, err := k8Client.Clientset.CoreV1().Pods(k8Client.GetCurrentNamespace()).Create(context.TODO(), &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeebe-0"},
+		Status: v1.PodStatus{
+			EphemeralContainerStatuses: []v1.ContainerStatus{
+				{Name: "debug-running", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// when
+	_, err = k8Client.awaitDebugContainerTermination("zeebe-0", "debug-running", 2*time.Second)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not terminated within given timeout")
+}
+
+func Test_ShouldReportFailedDebugContainerSetup(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	_, err := k8Client.Clientset.CoreV1().Pods(k8Client.GetCurrentNamespace()).Create(context.TODO(), &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeebe-0"},
+		Status: v1.PodStatus{
+			EphemeralContainerStatuses: []v1.ContainerStatus{
+				{Name: "debug-failed", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 100}}},
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// when
+	err = k8Client.awaitDebugContainerStarted("zeebe-0", "debug-failed", 5*time.Second)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed with exit code 100")
+}
+
+func Test_ShouldSkipToolSetupForToolsDebugImage(t *testing.T) {
+	// given
+	previousImage := DebugImage
+	DebugImage = ToolsDebugImage("1.0.0")
+	defer func() { DebugImage = previousImage }()
+
+	// when
+	cmd := withToolSetup("ip route show", "iproute2")
+
+	// then
+	assert.Equal(t, "gcr.io/zeebe-io/zbchaos-debug:1.0.0", DebugImage)
+	assert.Equal(t, "ip route show", cmd)
+}
+
+func Test_ShouldUseLatestToolsDebugImageForDevelopmentVersion(t *testing.T) {
+	// given
+
+	// when
+	image := ToolsDebugImage("development")
+
+	// then
+	assert.Equal(t, "gcr.io/zeebe-io/zbchaos-debug:latest", image)
+}
+
+func Test_ShouldSkipToolSetupForToolsDebugImageWithDigest(t *testing.T) {
+	// given
+	previousImage := DebugImage
+	DebugImage = "gcr.io/zeebe-io/zbchaos-debug@sha256:0123456789abcdef"
+	defer func() { DebugImage = previousImage }()
+
+	// when
+	cmd := withToolSetup("ip route show", "iproute2")
+
+	// then
+	assert.Equal(t, "ip route show", cmd)
+}
+
+func Test_ShouldSkipToolSetupIfDebugImageHasTools(t *testing.T) {
+	// given
+	previousImage := DebugImage
+	DebugImage = "registry.example.com:5000/mirror/zbchaos-debug:1.0.0"
+	DebugImageHasTools = true
+	defer func() {
+		DebugImage = previousImage
+		DebugImageHasTools = false
+	}()
+
+	// when
+	cmd := withToolSetup("ip route show", "iproute2")
+
+	// then
+	assert.Equal(t, "ip route show", cmd)
+}
+
+func Test_ShouldInstallToolsForFallbackDebugImage(t *testing.T) {
+	// given
+	previousImage := DebugImage
+	DebugImage = FallbackDebugImage
+	defer func() { DebugImage = previousImage }()
+
+	// when
+	cmd := withToolSetup("ip route show", "iproute2", "iptables")
+
+	// then
+	assert.Equal(t, "apt update && apt install -y iproute2 iptables && ip route show", cmd)
+}
+
+func Test_ShouldInstallToolsForOtherImages(t *testing.T) {
+	// given
+	previousImage := DebugImage
+	DebugImage = "registry.example.com:5000/mirror/zeebe:8.5.0"
+	defer func() { DebugImage = previousImage }()
+
+	// when
+	cmd := withToolSetup("ip route show", "iproute2", "iptables")
+
+	// then
+	assert.Equal(t, "apt update && apt install -y iproute2 iptables && ip route show", cmd)
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The name of the Zeebe process in the broker and gateway containers
+const zeebeProcessName = "java"
+
+// Pauses the Zeebe (java) process of the given pod via SIGSTOP, and resumes it via SIGCONT after the given duration.
+// The pod is not restarted, which means connections stay open and peers only see timeouts, similar to a long GC pause.
+func FreezeProcessOfPod(k8Client K8Client, podName string, duration time.Duration) error {
+	if duration <= 0 {
+		return fmt.Errorf("expected a positive duration after which the process of %s is resumed, but got %s", podName, duration)
+	}
+	freezeCmd, resumeCmd := buildFreezeCommands()
+	return applyFault(k8Client, podName, toolSetup("procps"), freezeCmd, resumeCmd, duration)
+}
+
+// Builds the commands to pause (SIGSTOP) and to resume (SIGCONT) the Zeebe process.
+func buildFreezeCommands() (string, string) {
+	return fmt.Sprintf("pkill -STOP -x %s", zeebeProcessName), fmt.Sprintf("pkill -CONT -x %s", zeebeProcessName)
+}
+
+// The signals which can be sent to the Zeebe process
+var supportedSignals = []string{"KILL", "TERM"}
+
+// How long we wait for the restart of the container, if the exec session is closed with an error
+const signalRestartTimeout = 30 * time.Second
+
+// Sends the given signal (e.g. KILL) to the Zeebe (java) process of the given pod, via exec into the container.
+// The pod is not deleted, which means the kubelet restarts the container on the same pod, with the same ip and volume.
+func SignalProcessOfPod(k8Client K8Client, podName string, signal string) error {
+	signal = strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	if !slices.Contains(supportedSignals, signal) {
+		return fmt.Errorf("expected signal to be one of %v, but got '%s'", supportedSignals, signal)
+	}
+
+	restartCount, err := k8Client.getContainerRestartCount(podName)
+	if err != nil {
+		return err
+	}
+
+	err = k8Client.ExecuteCmdOnPod([]string{"sh", "-c", buildSignalCommand(signal)}, podName)
+	if err != nil {
+		// the exec session might be closed with an error, since the container terminates with the process
+		LogVerbose("Failed to execute signal command on %s, will check whether the container has been restarted. Error: %s", podName, err.Error())
+		return k8Client.awaitContainerRestart(podName, restartCount, signalRestartTimeout)
+	}
+	return nil
+}
+
+// Builds the command to send the signal to all java processes. We look up the processes via /proc, since the
+// Zeebe image doesn't necessarily contain pkill. Fails if no java process is running.
+func buildSignalCommand(signal string) string {
+	return fmt.Sprintf(`found=0; for p in /proc/[0-9]*; do if [ "$(cat $p/comm 2>/dev/null)" = "%s" ]; then kill -s %s ${p#/proc/} && found=1; fi; done; [ $found = 1 ] || { echo "no %[1]s process found"; exit 1; }`,
+		zeebeProcessName, signal)
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ShouldRejectFreezeWithoutDuration(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+
+	// when
+	err := FreezeProcessOfPod(k8Client, "zeebe-0", 0)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected a positive duration")
+}
+
+func Test_ShouldBuildFreezeCommands(t *testing.T) {
+	// given
+
+	// when
+	freezeCmd, resumeCmd := buildFreezeCommands()
+
+	// then
+	assert.Equal(t, "pkill -STOP -x java", freezeCmd)
+	assert.Equal(t, "pkill -CONT -x java", resumeCmd)
+}
+
+func Test_ShouldResumeFrozenProcessAfterDuration(t *testing.T) {
+	// given
+	_, resumeCmd := buildFreezeCommands()
+
+	// when
+	cmd := healAfter(resumeCmd, 30*time.Second)
+
+	// then
+	assert.Equal(t, "sleep 30 && pkill -CONT -x java", cmd)
+}
+
+func Test_ShouldRejectUnsupportedSignal(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+
+	// when
+	err := SignalProcessOfPod(k8Client, "zeebe-0", "HUP")
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected signal to be one of [KILL TERM]")
+}
+
+func Test_ShouldBuildSignalCommand(t *testing.T) {
+	// given
+	signal := "TERM"
+
+	// when
+	cmd := buildSignalCommand(signal)
+
+	// then
+	assert.Contains(t, cmd, `[ "$(cat $p/comm 2>/dev/null)" = "java" ]`)
+	assert.Contains(t, cmd, "kill -s TERM ${p#/proc/}")
+	assert.Contains(t, cmd, `echo "no java process found"; exit 1;`)
+}
+
+func Test_ShouldAwaitContainerRestart(t *testing.T) {
+	// given
+	k8Client := CreateFakeClient()
+	_, err := k8Client.Clientset.CoreV1().Pods(k8Client.GetCurrentNamespace()).Create(context.TODO(), &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "zeebe-0"},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{Name: "zeebe", RestartCount: 2}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// when
+	err = k8Client.awaitContainerRestart("zeebe-0", 1, 5*time.Second)
+
+	// then
+	require.NoError(t, err)
+}
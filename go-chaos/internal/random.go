@@ -0,0 +1,113 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/zbc"
+)
+
+// RandomId can be used as node id or partition id, to choose a random broker or partition
+const RandomId = -2
+
+// RandomRole can be used as partition role, to choose a random replica of the partition
+const RandomRole = "RANDOM"
+
+// The seed is logged together with each random choice, such that a run can be replayed
+var randomSeed = time.Now().UnixNano()
+var random = rand.New(rand.NewSource(randomSeed))
+
+// Sets the seed of the random target selection, to replay the choices of a previous run
+func SetRandomSeed(seed int64) {
+	randomSeed = seed
+	random = rand.New(rand.NewSource(seed))
+}
+
+// Returns whether a random broker should be chosen for the given node id, partition id or role
+func IsRandomTarget(nodeId int, partitionId int, role string) bool {
+	return nodeId == RandomId || partitionId == RandomId || role == RandomRole
+}
+
+// Replaces the random node id, partition id or role with a random choice, based on the current topology.
+// A random role chooses a random replica of the partition, in this case the node id of the replica is returned.
+func ResolveRandomTarget(zbClient zbc.Client, nodeId int, partitionId int, role string) (int, int, string, error) {
+	if !IsRandomTarget(nodeId, partitionId, role) {
+		return nodeId, partitionId, role, nil
+	}
+
+	topology, err := GetTopology(zbClient)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	return chooseRandomTarget(topology, nodeId, partitionId, role)
+}
+
+func chooseRandomTarget(topology *pb.TopologyResponse, nodeId int, partitionId int, role string) (int, int, string, error) {
+	if nodeId == RandomId {
+		if topology.ClusterSize <= 0 {
+			return 0, 0, "", errors.New("expected to choose a random broker, but the cluster size is zero")
+		}
+		nodeId = random.Intn(int(topology.ClusterSize))
+		LogInfo("Chose random node id %d (seed %d)", nodeId, randomSeed)
+		return nodeId, partitionId, role, nil
+	}
+
+	if nodeId >= 0 {
+		return nodeId, partitionId, role, nil
+	}
+
+	if partitionId == RandomId {
+		if topology.PartitionsCount <= 0 {
+			return 0, 0, "", errors.New("expected to choose a random partition, but the partitions count is zero")
+		}
+		partitionId = 1 + random.Intn(int(topology.PartitionsCount))
+		LogInfo("Chose random partition id %d (seed %d)", partitionId, randomSeed)
+	}
+
+	if role == RandomRole {
+		replicas := GetPartitionReplicas(topology, partitionId)
+		if len(replicas) == 0 {
+			return 0, 0, "", fmt.Errorf("expected to choose a random replica of partition %d, but found none", partitionId)
+		}
+
+		replica := replicas[random.Intn(len(replicas))]
+		nodeId = replica.NodeId
+		role = replica.Role.String()
+		LogInfo("Chose random replica of partition %d, node id %d with role %s (seed %d)", partitionId, nodeId, role, randomSeed)
+	}
+
+	return nodeId, partitionId, role, nil
+}
+
+// Chooses the given count of node ids randomly, the chosen node ids are returned in ascending order.
+func ChooseRandomNodeIds(nodeIds []int, count int) ([]int, error) {
+	if count <= 0 || count > len(nodeIds) {
+		return nil, fmt.Errorf("expected to choose between 1 and %d brokers, but got %d", len(nodeIds), count)
+	}
+
+	var chosen []int
+	for _, index := range random.Perm(len(nodeIds))[:count] {
+		chosen = append(chosen, nodeIds[index])
+	}
+	sort.Ints(chosen)
+	LogInfo("Chose random node ids %v (seed %d)", chosen, randomSeed)
+	return chosen, nil
+}
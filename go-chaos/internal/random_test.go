@@ -0,0 +1,129 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTestTopology() *pb.TopologyResponse {
+	return &pb.TopologyResponse{
+		ClusterSize:     3,
+		PartitionsCount: 3,
+		Brokers: []*pb.BrokerInfo{
+			{NodeId: 2, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_FOLLOWER}, {PartitionId: 2, Role: pb.Partition_LEADER}}},
+			{NodeId: 0, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_LEADER}, {PartitionId: 3, Role: pb.Partition_FOLLOWER}}},
+			{NodeId: 1, Partitions: []*pb.Partition{{PartitionId: 2, Role: pb.Partition_FOLLOWER}, {PartitionId: 3, Role: pb.Partition_LEADER}}},
+		},
+	}
+}
+
+func Test_ShouldNotChangeFixedTarget(t *testing.T) {
+	// given
+	topology := createRandomTestTopology()
+
+	// when
+	nodeId, partitionId, role, err := chooseRandomTarget(topology, -1, 2, "LEADER")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, -1, nodeId)
+	assert.Equal(t, 2, partitionId)
+	assert.Equal(t, "LEADER", role)
+}
+
+func Test_ShouldChooseRandomNodeId(t *testing.T) {
+	// given
+	topology := createRandomTestTopology()
+	SetRandomSeed(42)
+
+	// when
+	nodeId, _, _, err := chooseRandomTarget(topology, RandomId, 1, "LEADER")
+
+	// then
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, nodeId, 0)
+	assert.Less(t, nodeId, 3)
+}
+
+func Test_ShouldChooseRandomReplicaOfPartition(t *testing.T) {
+	// given
+	topology := createRandomTestTopology()
+	SetRandomSeed(42)
+
+	// when
+	nodeId, partitionId, role, err := chooseRandomTarget(topology, -1, 3, RandomRole)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 3, partitionId)
+	assert.Contains(t, []int{0, 1}, nodeId)
+	if nodeId == 0 {
+		assert.Equal(t, "FOLLOWER", role)
+	} else {
+		assert.Equal(t, "LEADER", role)
+	}
+}
+
+func Test_ShouldReplayRandomChoicesWithSameSeed(t *testing.T) {
+	// given
+	topology := createRandomTestTopology()
+
+	// when
+	SetRandomSeed(7)
+	_, firstPartitionId, firstRole, err := chooseRandomTarget(topology, -1, RandomId, RandomRole)
+	require.NoError(t, err)
+	SetRandomSeed(7)
+	_, secondPartitionId, secondRole, err := chooseRandomTarget(topology, -1, RandomId, RandomRole)
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, firstPartitionId, secondPartitionId)
+	assert.Equal(t, firstRole, secondRole)
+	assert.GreaterOrEqual(t, firstPartitionId, 1)
+	assert.LessOrEqual(t, firstPartitionId, 3)
+}
+
+func Test_ShouldChooseRandomNodeIds(t *testing.T) {
+	// given
+	nodeIds := []int{0, 2, 4, 6, 8}
+	SetRandomSeed(13)
+
+	// when
+	chosen, err := ChooseRandomNodeIds(nodeIds, 3)
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, chosen, 3)
+	assert.IsIncreasing(t, chosen)
+	assert.Subset(t, nodeIds, chosen)
+}
+
+func Test_ShouldRejectInvalidRandomNodeIdCount(t *testing.T) {
+	// given
+	nodeIds := []int{0, 1, 2}
+
+	// when
+	_, tooMany := ChooseRandomNodeIds(nodeIds, 4)
+	_, none := ChooseRandomNodeIds(nodeIds, 0)
+
+	// then
+	require.Error(t, tooMany)
+	require.Error(t, none)
+}
@@ -15,31 +15,110 @@
 package internal
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 )
 
+// The tools which can be used to put stress on a pod
+const (
+	StressBackendStress   = "stress"
+	StressBackendStressNg = "stress-ng"
+)
+
+// The default intensity of the stress, which saturates the node completely
+const (
+	DefaultCpuWorkers    = 256
+	DefaultMemoryWorkers = 4
+	DefaultIoWorkers     = 256
+)
+
 type StressType struct {
 	IoStress  bool
 	CpuStress bool
 	MemStress bool
+
+	// The tool which is used to put the stress, defaults to stress
+	Backend string
+	// Number of workers spinning on sqrt(), stress-ng uses all CPUs for 0
+	CpuWorkers int
+	// The load per CPU worker in percent, only supported by stress-ng
+	CpuLoad int
+	// Number of workers spinning on malloc()/free()
+	MemWorkers int
+	// The bytes allocated per memory worker, e.g. 512M. Per default 256MB are allocated.
+	MemBytes string
+	// Number of workers spinning on sync()
+	IoWorkers int
 }
 
 func PutStressOnPod(k8Client K8Client, timeoutSec string, podName string, containerName string, stressType StressType) error {
-	stressCmd := []string{"stress", "--timeout", timeoutSec}
+	stressCmd, err := buildStressCommand(timeoutSec, stressType)
+	if err != nil {
+		return err
+	}
+	return k8Client.StartCommandViaDebugContainer(podName, containerName, DebugImage, toolSetup(stressBackend(stressType), "procps"), strings.Join(stressCmd, " "))
+}
+
+// Stops all stress processes, which have been started on the pod. Since the debug containers share the process
+// namespace of the target container, the processes are killed via a new debug container. Succeeds also if no
+// stress process is running. Returns the output of the stop command, which contains the count of stopped processes.
+func StopStressOnPod(k8Client K8Client, podName string, containerName string) (string, error) {
+	output, err := k8Client.ExecuteCommandViaDebugContainerWithOutput(podName, containerName, DebugImage, []string{"sh", "-c", buildStopStressCommand()}, debugContainerTimeout)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return lines[len(lines)-1], nil
+}
+
+// Builds the command to kill the processes of both stress backends, stress-ng names its workers e.g. stress-ng-cpu.
+func buildStopStressCommand() string {
+	return fmt.Sprintf(`count=0; for p in /proc/[0-9]*; do case "$(cat $p/comm 2>/dev/null)" in %s|%s*) kill -s KILL ${p#/proc/} 2>/dev/null && count=$((count + 1));; esac; done; echo "stopped $count stress processes"`,
+		StressBackendStress, StressBackendStressNg)
+}
+
+func stressBackend(stressType StressType) string {
+	if stressType.Backend == "" {
+		return StressBackendStress
+	}
+	return stressType.Backend
+}
+
+func buildStressCommand(timeoutSec string, stressType StressType) ([]string, error) {
+	backend := stressBackend(stressType)
+	if backend != StressBackendStress && backend != StressBackendStressNg {
+		return nil, fmt.Errorf("expected stress backend to be one of [%s, %s], but got '%s'", StressBackendStress, StressBackendStressNg, backend)
+	}
+
+	if stressType.CpuLoad < 0 || stressType.CpuLoad > 100 {
+		return nil, fmt.Errorf("expected cpu load to be a percentage between 0 and 100, but got %d", stressType.CpuLoad)
+	}
+	if stressType.CpuLoad > 0 && stressType.CpuLoad < 100 && backend != StressBackendStressNg {
+		return nil, fmt.Errorf("the cpu load can only be limited with the %s backend", StressBackendStressNg)
+	}
+
+	if stressType.CpuStress && (stressType.CpuWorkers < 0 || (stressType.CpuWorkers == 0 && backend != StressBackendStressNg)) {
+		return nil, fmt.Errorf("expected at least one cpu worker, only the %s backend starts one worker per CPU for 0, but got %d", StressBackendStressNg, stressType.CpuWorkers)
+	}
+
+	stressCmd := []string{backend, "--timeout", timeoutSec}
 	if stressType.CpuStress {
-		// Spawn N workers spinning on sqrt().
-		stressCmd = append(stressCmd, "--cpu", "256")
+		stressCmd = append(stressCmd, "--cpu", strconv.Itoa(stressType.CpuWorkers))
+		if backend == StressBackendStressNg && stressType.CpuLoad > 0 {
+			stressCmd = append(stressCmd, "--cpu-load", strconv.Itoa(stressType.CpuLoad))
+		}
 	}
 
 	if stressType.MemStress {
-		// Spawn N workers spinning on malloc()/free(). Per default alloc 256MB per worker.
-		stressCmd = append(stressCmd, "--vm", "4")
+		stressCmd = append(stressCmd, "--vm", strconv.Itoa(stressType.MemWorkers))
+		if stressType.MemBytes != "" {
+			stressCmd = append(stressCmd, "--vm-bytes", stressType.MemBytes)
+		}
 	}
 
 	if stressType.IoStress {
-		// Spawn N workers spinning on sync().
-		stressCmd = append(stressCmd, "--io", "256")
+		stressCmd = append(stressCmd, "--io", strconv.Itoa(stressType.IoWorkers))
 	}
-	cmdWithSetup := []string{"sh", "-c", "apt update && apt install -y stress procps && " + strings.Join(stressCmd, " ")}
-	return k8Client.ExecuteCommandViaDebugContainer(podName, containerName, "camunda/zeebe", cmdWithSetup)
+	return stressCmd, nil
 }
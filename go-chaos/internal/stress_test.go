@@ -0,0 +1,94 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ShouldBuildDefaultStressCommand(t *testing.T) {
+	// given
+	stressType := StressType{CpuStress: true, MemStress: true, IoStress: true,
+		CpuWorkers: DefaultCpuWorkers, MemWorkers: DefaultMemoryWorkers, IoWorkers: DefaultIoWorkers}
+
+	// when
+	cmd, err := buildStressCommand("30", stressType)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stress", "--timeout", "30", "--cpu", "256", "--vm", "4", "--io", "256"}, cmd)
+}
+
+func Test_ShouldBuildStressNgCommandWithCpuLoad(t *testing.T) {
+	// given
+	stressType := StressType{CpuStress: true, MemStress: true,
+		Backend: StressBackendStressNg, CpuWorkers: 0, CpuLoad: 80, MemWorkers: 2, MemBytes: "512M"}
+
+	// when
+	cmd, err := buildStressCommand("60", stressType)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stress-ng", "--timeout", "60", "--cpu", "0", "--cpu-load", "80", "--vm", "2", "--vm-bytes", "512M"}, cmd)
+}
+
+func Test_ShouldRejectCpuLoadWithoutStressNg(t *testing.T) {
+	// given
+	stressType := StressType{CpuStress: true, CpuWorkers: 1, CpuLoad: 80}
+
+	// when
+	_, err := buildStressCommand("30", stressType)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stress-ng")
+}
+
+func Test_ShouldRejectZeroCpuWorkersWithoutStressNg(t *testing.T) {
+	// given
+	stressType := StressType{CpuStress: true, CpuWorkers: 0}
+
+	// when
+	_, err := buildStressCommand("30", stressType)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected at least one cpu worker")
+}
+
+func Test_ShouldRejectUnknownStressBackend(t *testing.T) {
+	// given
+	stressType := StressType{CpuStress: true, Backend: "yes"}
+
+	// when
+	_, err := buildStressCommand("30", stressType)
+
+	// then
+	require.Error(t, err)
+}
+
+func Test_ShouldBuildStopStressCommand(t *testing.T) {
+	// given
+
+	// when
+	cmd := buildStopStressCommand()
+
+	// then
+	assert.Contains(t, cmd, `case "$(cat $p/comm 2>/dev/null)" in stress|stress-ng*) kill -s KILL ${p#/proc/}`)
+	assert.Contains(t, cmd, `echo "stopped $count stress processes"`)
+}
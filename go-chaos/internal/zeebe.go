@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,10 +32,13 @@ import (
 )
 
 func CreateZeebeClient(port int, credentials *ClientCredentials) (zbc.Client, error) {
-	endpoint := fmt.Sprintf("localhost:%d", port)
+	return CreateZeebeClientForAddress(fmt.Sprintf("localhost:%d", port), credentials)
+}
 
+// Creates a client, which connects via plaintext to the given gateway address, e.g. to the gateway service inside the cluster
+func CreateZeebeClientForAddress(address string, credentials *ClientCredentials) (zbc.Client, error) {
 	clientConfig := &zbc.ClientConfig{
-		GatewayAddress:         endpoint,
+		GatewayAddress:         address,
 		DialOpts:               []grpc.DialOption{},
 		UsePlaintextConnection: true,
 	}
@@ -97,12 +102,25 @@ func GetBrokerPodForNodeId(k8Client K8Client, brokerNodeId int32) (*v1.Pod, erro
 		return nil, err
 	}
 
-	if int(brokerNodeId) >= len(pods.Items) {
-		return nil, errors.New(fmt.Sprintf("Expected to find Broker with nodeId %d, but running pod count is %d. Be aware node id's start with zero.", brokerNodeId, len(pods.Items)))
+	// the pods are returned in alphabetical order, which means we can't use the index with 10 or more brokers
+	for i := range pods.Items {
+		nodeId, err := GetBrokerNodeIdOfPod(pods.Items[i].Name)
+		if err == nil && nodeId == brokerNodeId {
+			return &pods.Items[i], nil
+		}
 	}
+	return nil, errors.New(fmt.Sprintf("Expected to find Broker with nodeId %d, but running pod count is %d. Be aware node id's start with zero.", brokerNodeId, len(pods.Items)))
+}
 
-	// the following works since the pods are returned in alphabetical order (and end with '-id')
-	return &pods.Items[brokerNodeId], nil
+// Returns the node id of the broker pod with the given name, which is the ordinal of the StatefulSet pod,
+// e.g. 'zeebe-10' results in 10.
+func GetBrokerNodeIdOfPod(podName string) (int32, error) {
+	ordinal := podName[strings.LastIndex(podName, "-")+1:]
+	nodeId, err := strconv.ParseInt(ordinal, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("expected broker pod name '%s' to end with the node id", podName)
+	}
+	return int32(nodeId), nil
 }
 
 func GetBrokerNodeId(zbClient zbc.Client, partitionId int, role string) (int32, error) {
@@ -124,10 +142,89 @@ func GetBrokerNodeId(zbClient zbc.Client, partitionId int, role string) (int32,
 	return nodeId, nil
 }
 
+// PartitionReplica describes a replica of a partition, which is hosted by the broker with the node id.
+type PartitionReplica struct {
+	NodeId int
+	Role   pb.Partition_PartitionBrokerRole
+}
+
+// Returns the replicas of the given partition, which are part of the topology, ordered by the node id of their broker.
+// The order of the brokers in the topology is not stable, which is why we sort them (e.g. to replay a random choice).
+func GetPartitionReplicas(topologyResponse *pb.TopologyResponse, partitionId int) []PartitionReplica {
+	var replicas []PartitionReplica
+	for _, broker := range topologyResponse.Brokers {
+		for _, partition := range broker.Partitions {
+			if partition.PartitionId == int32(partitionId) {
+				replicas = append(replicas, PartitionReplica{NodeId: int(broker.NodeId), Role: partition.Role})
+			}
+		}
+	}
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i].NodeId < replicas[j].NodeId })
+	return replicas
+}
+
 func GetTopology(zbClient zbc.Client) (*pb.TopologyResponse, error) {
 	return zbClient.NewTopologyCommand().Send(context.TODO())
 }
 
+// Returns an error describing why the topology is not healthy, i.e. if not all brokers are part of the topology,
+// a partition has not all replicas or no leader, or a replica is not healthy. Returns nil for a healthy topology.
+func CheckTopologyHealth(topologyResponse *pb.TopologyResponse) error {
+	if len(topologyResponse.Brokers) != int(topologyResponse.ClusterSize) {
+		return fmt.Errorf("expected %d brokers in the topology, but found %d", topologyResponse.ClusterSize, len(topologyResponse.Brokers))
+	}
+
+	replicas := map[int32]int32{}
+	leaders := map[int32]bool{}
+	for _, broker := range topologyResponse.Brokers {
+		for _, partition := range broker.Partitions {
+			if partition.Health != pb.Partition_HEALTHY {
+				return fmt.Errorf("expected partition %d on broker %d to be healthy, but was %s", partition.PartitionId, broker.NodeId, partition.Health.String())
+			}
+			replicas[partition.PartitionId]++
+			if partition.Role == pb.Partition_LEADER {
+				leaders[partition.PartitionId] = true
+			}
+		}
+	}
+
+	for partitionId := int32(1); partitionId <= topologyResponse.PartitionsCount; partitionId++ {
+		if replicas[partitionId] != topologyResponse.ReplicationFactor {
+			return fmt.Errorf("expected %d replicas of partition %d, but found %d", topologyResponse.ReplicationFactor, partitionId, replicas[partitionId])
+		}
+		if !leaders[partitionId] {
+			return fmt.Errorf("expected partition %d to have a leader, but found none", partitionId)
+		}
+	}
+	return nil
+}
+
+// Waits until the topology is healthy, see CheckTopologyHealth. Returns the healthy topology.
+func AwaitHealthyTopology(zbClient zbc.Client, timeout time.Duration) (*pb.TopologyResponse, error) {
+	timedOut := time.After(timeout)
+	ticker := time.Tick(1 * time.Second)
+
+	// Keep checking until we're timed out
+	for {
+		select {
+		case <-timedOut:
+			return nil, fmt.Errorf("Awaited healthy topology, but timed out after %v", timeout)
+		case <-ticker:
+			topology, err := GetTopology(zbClient)
+			if err != nil {
+				LogVerbose("Failed to request topology. Will retry. %v", err)
+				continue
+			}
+
+			err = CheckTopologyHealth(topology)
+			if err == nil {
+				return topology, nil
+			}
+			LogVerbose("Topology is not healthy yet: %v. Wait for some seconds.", err)
+		}
+	}
+}
+
 func extractNodeId(topologyResponse *pb.TopologyResponse, partitionId int, role string) (int32, error) {
 	roleValue, exist := pb.Partition_PartitionBrokerRole_value[role]
 	if !exist {
@@ -16,6 +16,7 @@ package internal
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_ExtractNodeId(t *testing.T) {
@@ -481,3 +483,125 @@ func Test_ShouldSetCorrectPropertiesWhenUsingJobCompleter(t *testing.T) {
 	assert.Equal(t, int64(1), fakeClient.jobKey)
 	assert.Equal(t, int32(1), fakeClient.fakeActivateCommand.maxActivate)
 }
+
+func Test_GetPartitionReplicas(t *testing.T) {
+	// given
+	topology := createTopologyStub()
+
+	// when
+	replicas := GetPartitionReplicas(&topology, 2)
+
+	// then
+	assert.Equal(t, []PartitionReplica{
+		{NodeId: 0, Role: pb.Partition_FOLLOWER},
+		{NodeId: 1, Role: pb.Partition_LEADER},
+		{NodeId: 2, Role: pb.Partition_FOLLOWER},
+	}, replicas)
+}
+
+func Test_ShouldDetectHealthyTopology(t *testing.T) {
+	// given
+	topology := &pb.TopologyResponse{
+		ClusterSize: 2, PartitionsCount: 1, ReplicationFactor: 2,
+		Brokers: []*pb.BrokerInfo{
+			{NodeId: 0, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_LEADER, Health: pb.Partition_HEALTHY}}},
+			{NodeId: 1, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_FOLLOWER, Health: pb.Partition_HEALTHY}}},
+		},
+	}
+
+	// when
+	err := CheckTopologyHealth(topology)
+
+	// then
+	assert.NoError(t, err)
+}
+
+func Test_ShouldDetectUnhealthyTopology(t *testing.T) {
+	// given
+	unhealthy := &pb.TopologyResponse{
+		ClusterSize: 2, PartitionsCount: 1, ReplicationFactor: 2,
+		Brokers: []*pb.BrokerInfo{
+			{NodeId: 0, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_LEADER, Health: pb.Partition_HEALTHY}}},
+			{NodeId: 1, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_FOLLOWER, Health: pb.Partition_UNHEALTHY}}},
+		},
+	}
+	withoutLeader := &pb.TopologyResponse{
+		ClusterSize: 1, PartitionsCount: 1, ReplicationFactor: 1,
+		Brokers: []*pb.BrokerInfo{
+			{NodeId: 0, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_FOLLOWER, Health: pb.Partition_HEALTHY}}},
+		},
+	}
+	missingBroker := &pb.TopologyResponse{ClusterSize: 2, PartitionsCount: 1, ReplicationFactor: 1,
+		Brokers: []*pb.BrokerInfo{
+			{NodeId: 0, Partitions: []*pb.Partition{{PartitionId: 1, Role: pb.Partition_LEADER, Health: pb.Partition_HEALTHY}}},
+		},
+	}
+
+	// when
+	unhealthyErr := CheckTopologyHealth(unhealthy)
+	withoutLeaderErr := CheckTopologyHealth(withoutLeader)
+	missingBrokerErr := CheckTopologyHealth(missingBroker)
+
+	// then
+	assert.ErrorContains(t, unhealthyErr, "UNHEALTHY")
+	assert.ErrorContains(t, withoutLeaderErr, "leader")
+	assert.ErrorContains(t, missingBrokerErr, "expected 2 brokers")
+}
+
+func Test_ShouldGetBrokerPodForNodeIdWithTenOrMoreBrokers(t *testing.T) {
+	// given
+	selector, err := metav1.ParseToLabelSelector(getSelfManagedBrokerLabels())
+	require.NoError(t, err)
+	k8Client := CreateFakeClient()
+	for nodeId := 0; nodeId <= 10; nodeId++ {
+		k8Client.CreatePodWithLabelsAndName(t, selector, fmt.Sprintf("zeebe-%d", nodeId))
+	}
+
+	// when
+	pod, err := GetBrokerPodForNodeId(k8Client, 2)
+	require.NoError(t, err)
+	lastPod, err := GetBrokerPodForNodeId(k8Client, 10)
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, "zeebe-2", pod.Name)
+	assert.Equal(t, "zeebe-10", lastPod.Name)
+}
+
+func Test_ShouldFailToGetBrokerPodForUnknownNodeId(t *testing.T) {
+	// given
+	selector, err := metav1.ParseToLabelSelector(getSelfManagedBrokerLabels())
+	require.NoError(t, err)
+	k8Client := CreateFakeClient()
+	k8Client.CreatePodWithLabelsAndName(t, selector, "zeebe-0")
+
+	// when
+	_, err = GetBrokerPodForNodeId(k8Client, 1)
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Expected to find Broker with nodeId 1")
+}
+
+func Test_ShouldGetBrokerNodeIdOfPod(t *testing.T) {
+	// given
+	podName := "camunda-platform-zeebe-12"
+
+	// when
+	nodeId, err := GetBrokerNodeIdOfPod(podName)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int32(12), nodeId)
+}
+
+func Test_ShouldFailToGetBrokerNodeIdOfPodWithoutOrdinal(t *testing.T) {
+	// given
+	podName := "zeebe-gateway"
+
+	// when
+	_, err := GetBrokerNodeIdOfPod(podName)
+
+	// then
+	require.Error(t, err)
+}
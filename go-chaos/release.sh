@@ -42,6 +42,12 @@ echo "Building docker image $dockerImage:$RELEASE_VERSION"
 docker build -t "$dockerImage:$RELEASE_VERSION" .
 docker push "$dockerImage:$RELEASE_VERSION"
 
+debugImage="gcr.io/zeebe-io/zbchaos-debug"
+echo "Building debug docker image $debugImage:$RELEASE_VERSION"
+docker build -t "$debugImage:$RELEASE_VERSION" -t "$debugImage:latest" debug/
+docker push "$debugImage:$RELEASE_VERSION"
+docker push "$debugImage:latest"
+
 echo "Update deployment.yaml"
 sed -i "s/TAG/$RELEASE_VERSION/g" deploy/deployment.yaml
 
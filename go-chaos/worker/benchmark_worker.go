@@ -0,0 +1,88 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/camunda/zeebe-chaos/go-chaos/internal"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+)
+
+// The error code of the BPMN errors thrown by the benchmark worker, which can be caught by an error boundary event
+const BenchmarkErrorCode = "zbchaos-benchmark-error"
+
+// Configures how the benchmark worker handles the jobs. The percentages define which share of the jobs is failed,
+// rejected with a BPMN error or never completed (such that they time out), all other jobs are completed.
+type BenchmarkWorkerConfig struct {
+	CompletionDelay     time.Duration
+	FailurePercentage   float64
+	BpmnErrorPercentage float64
+	TimeoutPercentage   float64
+}
+
+type benchmarkJobOutcome int
+
+const (
+	completeJob benchmarkJobOutcome = iota
+	failJob
+	throwBpmnError
+	timeOutJob
+)
+
+// Chooses the outcome of a job for the given roll, which is a random number between 0 (inclusive) and 100 (exclusive)
+func (cfg BenchmarkWorkerConfig) chooseOutcome(roll float64) benchmarkJobOutcome {
+	if roll < cfg.FailurePercentage {
+		return failJob
+	}
+	if roll < cfg.FailurePercentage+cfg.BpmnErrorPercentage {
+		return throwBpmnError
+	}
+	if roll < cfg.FailurePercentage+cfg.BpmnErrorPercentage+cfg.TimeoutPercentage {
+		return timeOutJob
+	}
+	return completeJob
+}
+
+// Handles a benchmark job, the roll (between 0 and 100) decides whether the job is completed, failed,
+// rejected with a BPMN error or not completed at all. Failed jobs are retried, as long as they have retries left.
+func HandleBenchmarkJob(client worker.JobClient, job entities.Job, cfg BenchmarkWorkerConfig, roll float64) {
+	ctx := context.Background()
+
+	outcome := cfg.chooseOutcome(roll)
+	if outcome == timeOutJob {
+		internal.LogVerbose("Don't complete job %d, such that it times out", job.Key)
+		return
+	}
+
+	time.Sleep(cfg.CompletionDelay)
+
+	var err error
+	switch outcome {
+	case failJob:
+		internal.LogVerbose("Fail job %d", job.Key)
+		_, err = client.NewFailJobCommand().JobKey(job.Key).Retries(job.Retries - 1).ErrorMessage("Job failed by the zbchaos benchmark worker").Send(ctx)
+	case throwBpmnError:
+		internal.LogVerbose("Throw BPMN error for job %d", job.Key)
+		_, err = client.NewThrowErrorCommand().JobKey(job.Key).ErrorCode(BenchmarkErrorCode).ErrorMessage("BPMN error thrown by the zbchaos benchmark worker").Send(ctx)
+	default:
+		_, err = client.NewCompleteJobCommand().JobKey(job.Key).Send(ctx)
+	}
+	if err != nil {
+		internal.LogInfo("Failed to handle job %d: %s", job.Key, err.Error())
+	}
+}
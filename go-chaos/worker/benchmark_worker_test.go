@@ -0,0 +1,103 @@
+// Copyright 2026 Camunda Services GmbH
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+var benchmarkJob = entities.Job{ActivatedJob: &pb.ActivatedJob{Key: 123, Retries: 3}}
+
+var benchmarkCfg = BenchmarkWorkerConfig{FailurePercentage: 10, BpmnErrorPercentage: 5, TimeoutPercentage: 2.5}
+
+func Test_ShouldChooseBenchmarkJobOutcomeByPercentages(t *testing.T) {
+	// given
+	cfg := benchmarkCfg
+
+	// when
+	outcomes := []benchmarkJobOutcome{cfg.chooseOutcome(0), cfg.chooseOutcome(9.9), cfg.chooseOutcome(10), cfg.chooseOutcome(14.9),
+		cfg.chooseOutcome(15), cfg.chooseOutcome(17.4), cfg.chooseOutcome(17.5), cfg.chooseOutcome(99.9)}
+
+	// then
+	assert.Equal(t, []benchmarkJobOutcome{failJob, failJob, throwBpmnError, throwBpmnError, timeOutJob, timeOutJob, completeJob, completeJob}, outcomes)
+}
+
+func Test_ShouldCompleteAllBenchmarkJobsPerDefault(t *testing.T) {
+	// given
+	cfg := BenchmarkWorkerConfig{}
+
+	// when
+	outcome := cfg.chooseOutcome(0)
+
+	// then
+	assert.Equal(t, completeJob, outcome)
+}
+
+func Test_ShouldCompleteBenchmarkJob(t *testing.T) {
+	// given
+	fakeJobClient := &FakeJobClient{}
+
+	// when
+	HandleBenchmarkJob(fakeJobClient, benchmarkJob, benchmarkCfg, 50)
+
+	// then
+	assert.True(t, fakeJobClient.Succeeded)
+	assert.Equal(t, 123, fakeJobClient.Key)
+}
+
+func Test_ShouldFailBenchmarkJobWithDecreasedRetries(t *testing.T) {
+	// given
+	fakeJobClient := &FakeJobClient{}
+
+	// when
+	HandleBenchmarkJob(fakeJobClient, benchmarkJob, benchmarkCfg, 5)
+
+	// then
+	assert.True(t, fakeJobClient.Failed)
+	assert.False(t, fakeJobClient.Succeeded)
+	assert.Equal(t, 123, fakeJobClient.Key)
+	assert.Equal(t, 2, fakeJobClient.RetriesVal)
+}
+
+func Test_ShouldThrowBpmnErrorForBenchmarkJob(t *testing.T) {
+	// given
+	fakeJobClient := &FakeJobClient{}
+
+	// when
+	HandleBenchmarkJob(fakeJobClient, benchmarkJob, benchmarkCfg, 12)
+
+	// then
+	assert.True(t, fakeJobClient.ThrownError)
+	assert.False(t, fakeJobClient.Succeeded)
+	assert.Equal(t, 123, fakeJobClient.Key)
+	assert.Equal(t, BenchmarkErrorCode, fakeJobClient.ErrorCode)
+}
+
+func Test_ShouldNotCompleteTimedOutBenchmarkJob(t *testing.T) {
+	// given
+	fakeJobClient := &FakeJobClient{}
+
+	// when
+	HandleBenchmarkJob(fakeJobClient, benchmarkJob, benchmarkCfg, 16)
+
+	// then
+	assert.False(t, fakeJobClient.Succeeded)
+	assert.False(t, fakeJobClient.Failed)
+	assert.False(t, fakeJobClient.ThrownError)
+}
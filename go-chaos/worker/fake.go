@@ -30,8 +30,10 @@ type FakeJobClient struct {
 	RetriesVal   int
 	RetryBackoff time.Duration
 	ErrorMsg     string
+	ErrorCode    string
 	Failed       bool
 	Succeeded    bool
+	ThrownError  bool
 	Variables    interface{}
 }
 
@@ -99,3 +101,37 @@ func (f *FakeFailClient) ErrorMessage(errorMsg string) commands.FailJobCommandSt
 func (f *FakeFailClient) Send(ctx context.Context) (*pb.FailJobResponse, error) {
 	return &pb.FailJobResponse{}, nil
 }
+
+// Fake THROW ERROR Client
+
+func (f *FakeJobClient) NewThrowErrorCommand() commands.ThrowErrorCommandStep1 {
+	f.ThrownError = true
+	return &FakeThrowErrorClient{JobClient: f}
+}
+
+type FakeThrowErrorClient struct {
+	commands.ThrowErrorCommandStep1
+	commands.ThrowErrorCommandStep2
+	commands.DispatchThrowErrorCommand
+
+	JobClient *FakeJobClient
+}
+
+func (f *FakeThrowErrorClient) JobKey(key int64) commands.ThrowErrorCommandStep2 {
+	f.JobClient.Key = int(key)
+	return f
+}
+
+func (f *FakeThrowErrorClient) ErrorCode(errorCode string) commands.DispatchThrowErrorCommand {
+	f.JobClient.ErrorCode = errorCode
+	return f
+}
+
+func (f *FakeThrowErrorClient) ErrorMessage(errorMsg string) commands.DispatchThrowErrorCommand {
+	f.JobClient.ErrorMsg = errorMsg
+	return f
+}
+
+func (f *FakeThrowErrorClient) Send(ctx context.Context) (*pb.ThrowErrorResponse, error) {
+	return &pb.ThrowErrorResponse{}, nil
+}